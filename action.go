@@ -0,0 +1,104 @@
+package main
+
+// Action represents a game command which can be triggered from any input device.
+type Action int
+
+// Action constants for commands on the board.
+const (
+	UndoAction Action = iota
+	RedoAction
+	ResetAction
+	NewGameAction
+	MuteAction
+	PrevBranchAction
+	NextBranchAction
+	EditAction
+	ShareAction
+	StatsAction
+	DailyAction
+	PacksAction
+	TutorialAction
+)
+
+// String returns the string representation of the action.
+func (a Action) String() string {
+	switch a {
+	case UndoAction:
+		return "Undo"
+	case RedoAction:
+		return "Redo"
+	case ResetAction:
+		return "Reset"
+	case NewGameAction:
+		return "New Game"
+	case MuteAction:
+		return "Mute"
+	case PrevBranchAction:
+		return "Previous Branch"
+	case NextBranchAction:
+		return "Next Branch"
+	case EditAction:
+		return "Edit"
+	case ShareAction:
+		return "Share"
+	case StatsAction:
+		return "Stats"
+	case DailyAction:
+		return "Daily"
+	case PacksAction:
+		return "Packs"
+	case TutorialAction:
+		return "Tutorial"
+	}
+	return "unknown Action"
+}
+
+// perform applies the action to the board or the settings.
+func (g *GameState) perform(a Action) error {
+	if g.Locked && a != MuteAction {
+		g.emit(&GameEvent{Kind: BumpEvent})
+		return nil
+	}
+	switch a {
+	case UndoAction, RedoAction, ResetAction, PrevBranchAction, NextBranchAction:
+		if g.historyLocked() {
+			g.emit(&GameEvent{Kind: BumpEvent})
+			return nil
+		}
+	}
+	switch a {
+	case UndoAction:
+		g.Board.Undo()
+	case RedoAction:
+		g.Board.Redo()
+	case ResetAction:
+		g.Board.Reset()
+	case NewGameAction:
+		if g.Daily != nil || g.Pack != nil {
+			// the daily puzzle and puzzles of packs are not replaced, but left for a random game
+			return g.playRandom()
+		}
+		return g.Board.NewGame()
+	case PrevBranchAction:
+		g.Board.SwitchBranch(-1)
+	case NextBranchAction:
+		g.Board.SwitchBranch(1)
+	case MuteAction:
+		g.Settings.Muted = !g.Settings.Muted
+		// refresh the label of the button
+		g.resized = g.layout
+	case EditAction:
+		return g.edit()
+	case ShareAction:
+		g.share()
+	case StatsAction:
+		return g.showStats()
+	case DailyAction:
+		return g.playDaily()
+	case PacksAction:
+		return g.showPacks()
+	case TutorialAction:
+		return g.showTutorial()
+	}
+	return nil
+}
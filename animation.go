@@ -0,0 +1,101 @@
+package main
+
+import (
+	"math"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// SLIDE_SECONDS_PER_CELL is the duration of a slide per cell at normal speed.
+const SLIDE_SECONDS_PER_CELL = 0.04
+
+// SLIDE_MIN_SECONDS is the minimum duration of a slide at normal speed.
+const SLIDE_MIN_SECONDS = 0.12
+
+// SLIDE_FAST_FACTOR is how many times faster slides are at fast speed.
+const SLIDE_FAST_FACTOR = 3
+
+// Slide is an animation of an actor sliding from a cell to another.
+type Slide struct {
+	hyper.Color
+	From, To hyper.Point
+	frame    int
+	frames   int
+}
+
+// Progress returns the eased progress of the slide between 0 and 1.
+func (s *Slide) Progress() float32 {
+	if s.frames < 1 {
+		return 1
+	}
+	t := float64(s.frame) / float64(s.frames)
+	// ease out cubic
+	return float32(1 - math.Pow(1-t, 3))
+}
+
+// Animator plays slides of actors one at a time.
+type Animator struct {
+	*Settings
+	current *Slide
+}
+
+// NewAnimator creates a new Animator following the animation speed in the settings.
+func NewAnimator(s *Settings) *Animator {
+	return &Animator{Settings: s}
+}
+
+// Start starts sliding the actor of the given color, replacing the current slide.
+// Nothing happens if animations are turned off.
+func (a *Animator) Start(c hyper.Color, from, to hyper.Point) {
+	if a.AnimationSpeed == AnimationOff {
+		a.Stop()
+		return
+	}
+	diff := to.Sub(from)
+	distance := diff.Abs()
+	seconds := max(SLIDE_MIN_SECONDS, float64(distance.X+distance.Y)*SLIDE_SECONDS_PER_CELL)
+	if a.AnimationSpeed == AnimationFast {
+		seconds /= SLIDE_FAST_FACTOR
+	}
+	a.current = &Slide{
+		Color:  c,
+		From:   from,
+		To:     to,
+		frames: int(math.Ceil(seconds * float64(ebiten.TPS()))),
+	}
+}
+
+// Stop finishes the current slide immediately.
+func (a *Animator) Stop() {
+	a.current = nil
+}
+
+// Animating returns true if a slide is in progress.
+func (a *Animator) Animating() bool {
+	return a.current != nil
+}
+
+// Update advances the current slide by a frame.
+func (a *Animator) Update() {
+	if a.current == nil {
+		return
+	}
+	a.current.frame++
+	if a.current.frame >= a.current.frames {
+		a.current = nil
+	}
+}
+
+// Position returns the screen position of the sliding actor of the given color.
+// It returns false if the actor is not sliding.
+func (a *Animator) Position(c hyper.Color, cellSize float32) (Position, bool) {
+	if a.current == nil || a.current.Color != c {
+		return Position{}, false
+	}
+	from := NewPosition(a.current.From, cellSize)
+	to := NewPosition(a.current.To, cellSize)
+	t := a.current.Progress()
+	diff := to.Sub(from)
+	return from.Add(diff.Mul(Position{t, t})), true
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+// SOUNDS_DIR is the directory in the assets to load sound files from.
+// Sounds without a file are synthesized.
+const SOUNDS_DIR = "sounds"
+
+// MUSIC_FILE is the name of the optional background music file in SOUNDS_DIR.
+const MUSIC_FILE = "music.wav"
+
+// MUSIC_VOLUME is the volume of the background music relative to sound effects.
+const MUSIC_VOLUME = 0.5
+
+// SAMPLE_RATE is the sample rate of the audio output in Hz.
+const SAMPLE_RATE = 44100
+
+// Sound represents a sound effect.
+type Sound int
+
+// Sound constants.
+const (
+	SlideSound Sound = iota
+	BumpSound
+	GoalSound
+	UndoSound
+	ClickSound
+)
+
+// AllSounds contains all sound effects.
+var AllSounds = []Sound{SlideSound, BumpSound, GoalSound, UndoSound, ClickSound}
+
+// String returns the string representation of the sound.
+func (s Sound) String() string {
+	switch s {
+	case SlideSound:
+		return "Slide"
+	case BumpSound:
+		return "Bump"
+	case GoalSound:
+		return "Goal"
+	case UndoSound:
+		return "Undo"
+	case ClickSound:
+		return "Click"
+	}
+	return "unknown Sound"
+}
+
+// FileName returns the name of the file in SOUNDS_DIR which replaces the synthesized sound.
+func (s Sound) FileName() string {
+	switch s {
+	case SlideSound:
+		return "slide.wav"
+	case BumpSound:
+		return "bump.wav"
+	case GoalSound:
+		return "goal.wav"
+	case UndoSound:
+		return "undo.wav"
+	case ClickSound:
+		return "click.wav"
+	}
+	return ""
+}
+
+// AudioBackend is an interface for playing sounds on a device.
+type AudioBackend interface {
+	// Play plays the sound effect once at the volume in [0, 1].
+	Play(s Sound, volume float64)
+	// SetMusicVolume plays the background music at the volume in [0, 1], or pauses it at 0.
+	SetMusicVolume(volume float64)
+}
+
+// NopAudioBackend is an AudioBackend which plays nothing, e.g. for headless tests.
+type NopAudioBackend struct{}
+
+// Play does nothing.
+func (NopAudioBackend) Play(s Sound, volume float64) {}
+
+// SetMusicVolume does nothing.
+func (NopAudioBackend) SetMusicVolume(volume float64) {}
+
+// AudioManager plays sounds for GameEvents and board events according to the settings.
+type AudioManager struct {
+	*Settings
+	Backend AudioBackend
+}
+
+// NewAudioManager creates and returns an AudioManager which plays sounds with the backend.
+func NewAudioManager(s *Settings, b AudioBackend) *AudioManager {
+	return &AudioManager{
+		Settings: s,
+		Backend:  b,
+	}
+}
+
+// volume returns the volume of sound effects in [0, 1].
+func (m *AudioManager) volume() float64 {
+	if m.Muted {
+		return 0
+	}
+	return m.Volume
+}
+
+// Play plays the sound effect unless muted.
+func (m *AudioManager) Play(s Sound) {
+	if v := m.volume(); v > 0 {
+		m.Backend.Play(s, v)
+	}
+}
+
+// Update applies the settings to the background music.
+func (m *AudioManager) Update() {
+	m.Backend.SetMusicVolume(m.volume() * MUSIC_VOLUME)
+}
+
+// HandleGameEvent plays the sound for the event.
+func (m *AudioManager) HandleGameEvent(e *GameEvent) {
+	switch e.Kind {
+	case BumpEvent:
+		m.Play(BumpSound)
+	case ClickEvent:
+		m.Play(ClickSound)
+	}
+}
+
+// HandleBoardEvent plays the sound for the event of the board.
+func (m *AudioManager) HandleBoardEvent(e *hyper.Event) {
+	switch e.Kind {
+	case hyper.MovedEvent, hyper.RedoneEvent, hyper.JumpedEvent:
+		m.Play(SlideSound)
+	case hyper.UndoneEvent, hyper.ResetEvent:
+		m.Play(UndoSound)
+	case hyper.GoalReachedEvent:
+		m.Play(GoalSound)
+	}
+}
+
+// tone is a sine wave sweeping from one frequency to another while fading out.
+type tone struct {
+	from, to float64 // Hz
+	seconds  float64
+	gain     float64 // in [0, 1]
+}
+
+// soundTones defines the synthesized sounds used when no sound file is available.
+var soundTones = map[Sound][]tone{
+	SlideSound: {{520, 260, 0.12, 0.3}},
+	BumpSound:  {{110, 70, 0.08, 0.6}},
+	GoalSound:  {{523, 523, 0.09, 0.4}, {659, 659, 0.09, 0.4}, {784, 784, 0.09, 0.4}, {1047, 1047, 0.25, 0.4}},
+	UndoSound:  {{260, 520, 0.1, 0.3}},
+	ClickSound: {{1200, 1200, 0.02, 0.2}},
+}
+
+// synthesize returns 16-bit little endian stereo PCM of the tones played in sequence.
+func synthesize(tones []tone, sampleRate int) []byte {
+	buf := []byte{}
+	attack := float64(sampleRate) * 0.005 // fade in to avoid clicks
+	for _, t := range tones {
+		n := int(t.seconds * float64(sampleRate))
+		phase := 0.0
+		for i := range n {
+			p := float64(i) / float64(n)
+			freq := t.from + (t.to-t.from)*p
+			phase += 2 * math.Pi * freq / float64(sampleRate)
+			envelope := min(1, float64(i)/attack) * (1 - p)
+			v := int16(math.Sin(phase) * t.gain * envelope * math.MaxInt16)
+			buf = binary.LittleEndian.AppendUint16(buf, uint16(v)) // left
+			buf = binary.LittleEndian.AppendUint16(buf, uint16(v)) // right
+		}
+	}
+	return buf
+}
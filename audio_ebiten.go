@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+)
+
+// EbitenAudioBackend is an AudioBackend which plays sounds with ebiten/audio.
+type EbitenAudioBackend struct {
+	context *audio.Context
+	sounds  map[Sound][]byte // 16-bit stereo PCM
+	music   *audio.Player    // nil if there is no music file
+}
+
+// NewEbitenAudioBackend creates an EbitenAudioBackend with sounds loaded from SOUNDS_DIR, or synthesized if missing.
+func NewEbitenAudioBackend(r *ResourceLoader) (*EbitenAudioBackend, error) {
+	context := audio.CurrentContext()
+	if context == nil {
+		context = audio.NewContext(SAMPLE_RATE)
+	}
+	b := &EbitenAudioBackend{
+		context: context,
+		sounds:  map[Sound][]byte{},
+	}
+
+	for _, s := range AllSounds {
+		stream, err := b.decode(r, s.FileName())
+		if errors.Is(err, fs.ErrNotExist) {
+			b.sounds[s] = synthesize(soundTones[s], context.SampleRate())
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		pcm, err := io.ReadAll(stream)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode %s: %w", s.FileName(), err)
+		}
+		b.sounds[s] = pcm
+	}
+
+	stream, err := b.decode(r, MUSIC_FILE)
+	if errors.Is(err, fs.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.music, err = context.NewPlayer(audio.NewInfiniteLoop(stream, stream.Length()))
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// decode decodes the WAV file in SOUNDS_DIR.
+func (b *EbitenAudioBackend) decode(r *ResourceLoader, name string) (*wav.Stream, error) {
+	data, err := r.Sound(path.Join(SOUNDS_DIR, name))
+	if err != nil {
+		return nil, err
+	}
+	stream, err := wav.DecodeWithSampleRate(b.context.SampleRate(), bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", name, err)
+	}
+	return stream, nil
+}
+
+// Play plays the sound effect once at the volume.
+func (b *EbitenAudioBackend) Play(s Sound, volume float64) {
+	pcm, ok := b.sounds[s]
+	if !ok {
+		return
+	}
+	p := b.context.NewPlayerFromBytes(pcm)
+	p.SetVolume(volume)
+	p.Play()
+}
+
+// SetMusicVolume plays the background music at the volume, or pauses it at 0.
+func (b *EbitenAudioBackend) SetMusicVolume(volume float64) {
+	if b.music == nil {
+		return
+	}
+	if volume <= 0 {
+		b.music.Pause()
+		return
+	}
+	b.music.SetVolume(volume)
+	if !b.music.IsPlaying() {
+		b.music.Play()
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"time"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+// AUTOSAVE_NAME is the name of the saved game in the storage.
+const AUTOSAVE_NAME = "autosave.json"
+
+// AUTOSAVE_INTERVAL is the minimum interval between autosaves.
+const AUTOSAVE_INTERVAL = 3 * time.Second
+
+// Autosaver saves the game in progress to the storage when the board is changed, at most once per AUTOSAVE_INTERVAL.
+type Autosaver struct {
+	Storage
+	game        *GameState   // game being saved
+	board       *hyper.Board // board of the game being saved
+	unsubscribe func()
+	dirty       bool // whether the board is changed since the last save
+	saved       time.Time
+}
+
+// NewAutosaver creates an Autosaver saving to the storage.
+func NewAutosaver(s Storage) *Autosaver {
+	return &Autosaver{Storage: s}
+}
+
+// Update follows the game, and saves it if its board has been changed and AUTOSAVE_INTERVAL has passed since the last save.
+func (a *Autosaver) Update(g *GameState) {
+	if g != a.game || g.Board != a.board {
+		a.Flush()
+		if a.unsubscribe != nil {
+			a.unsubscribe()
+		}
+		b := g.Board
+		a.game, a.board = g, b
+		a.unsubscribe = b.Subscribe(func(e *hyper.Event) {
+			a.dirty = true
+		})
+		a.dirty = true
+	}
+	if a.dirty && time.Since(a.saved) >= AUTOSAVE_INTERVAL {
+		a.Flush()
+	}
+}
+
+// Flush saves the board immediately if it has been changed.
+func (a *Autosaver) Flush() {
+	if a.board == nil || !a.dirty {
+		return
+	}
+	a.dirty = false
+	a.saved = time.Now()
+	buf := &bytes.Buffer{}
+	if err := a.game.savedGame().Write(buf); err != nil {
+		log.Println(err)
+		return
+	}
+	if err := a.Storage.Store(AUTOSAVE_NAME, buf.Bytes()); err != nil {
+		log.Println(err)
+	}
+}
+
+// Resumable returns the saved game and its board if it is in progress, that is, moves are made and the goal is not reached yet.
+func (a *Autosaver) Resumable() (*hyper.SavedGame, *hyper.Board, bool) {
+	data, err := a.Storage.Load(AUTOSAVE_NAME)
+	if err != nil {
+		return nil, nil, false
+	}
+	saved, err := hyper.ReadSavedGame(bytes.NewReader(data))
+	if err != nil {
+		log.Println(err)
+		return nil, nil, false
+	}
+	b, err := saved.NewBoard(DefaultPlacement)
+	if err != nil {
+		log.Println(err)
+		return nil, nil, false
+	}
+	if len(b.HistoryTree().Children) == 0 || b.Goaled {
+		return nil, nil, false
+	}
+	return saved, b, true
+}
+
+// savedGame returns the game in progress with the daily puzzle or the puzzle of a pack played in it.
+func (g *GameState) savedGame() *hyper.SavedGame {
+	s := hyper.NewSavedGame(g.Board)
+	if g.Daily != nil {
+		s.Daily = g.Daily.Date.Format(hyper.DAILY_DATE_FORMAT)
+	}
+	if g.Pack != nil {
+		s.Pack = g.Pack.Browser.pack().Title
+		s.Index = g.Pack.Index
+	}
+	return s
+}
+
+// resume switches to the game of the board of the saved game, which continues the daily puzzle or the puzzle of a pack played in it.
+// The puzzle of a pack is played as a random game if the pack is no longer available.
+func (g *GameState) resume(saved *hyper.SavedGame, b *hyper.Board) error {
+	next, err := g.play(b)
+	if err != nil {
+		return err
+	}
+	if saved.Daily != "" {
+		date, err := time.Parse(hyper.DAILY_DATE_FORMAT, saved.Daily)
+		if err != nil {
+			return err
+		}
+		next.Daily = LoadDailyMode(g.Storage, date)
+	}
+	if saved.Pack != "" {
+		browser, err := NewPackState(g, LoadPacks(g.ResourceLoader, PACKS_DIR), g.Storage)
+		if err != nil {
+			return err
+		}
+		if browser.show(saved.Pack) && saved.Index < len(browser.pack().Puzzles) {
+			next.Pack = &PackRun{Browser: browser, Index: saved.Index}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"image"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// BRANCH_SPACING is the distance between nodes in the branch panel in logical pixels.
+const BRANCH_SPACING = 16 // px
+
+// branchNode is a node of the history tree placed in the branch panel.
+type branchNode struct {
+	*hyper.Node
+	Position
+}
+
+// BranchPanel shows the history tree with a column per move and a row per branch.
+// Clicking a node jumps to it.
+type BranchPanel struct {
+	nodes   []*branchNode
+	current *branchNode
+	rect    image.Rectangle
+	scale   float32
+}
+
+// Update places the nodes of the tree in the rectangle, scrolling so that the current node is visible.
+func (p *BranchPanel) Update(root, current *hyper.Node, rect image.Rectangle, scale float64) {
+	p.nodes = p.nodes[:0]
+	p.current = nil
+	p.rect = rect
+	p.scale = float32(scale)
+	spacing := BRANCH_SPACING * p.scale
+
+	// depth-first, so that a node is placed on the row of its first child
+	row := 0
+	var place func(n *hyper.Node)
+	place = func(n *hyper.Node) {
+		node := &branchNode{n, Position{float32(n.Steps) * spacing, float32(row) * spacing}}
+		p.nodes = append(p.nodes, node)
+		if n == current {
+			p.current = node
+		}
+		for i, child := range n.Children {
+			if i > 0 {
+				row++
+			}
+			place(child)
+		}
+	}
+	place(root)
+
+	// scroll
+	offset := Position{float32(rect.Min.X) + spacing, float32(rect.Min.Y) + spacing}
+	if p.current != nil {
+		if over := p.current.X - (float32(rect.Dx()) - 2*spacing); over > 0 {
+			offset.X -= over
+		}
+		if over := p.current.Y - (float32(rect.Dy()) - 2*spacing); over > 0 {
+			offset.Y -= over
+		}
+	}
+	for _, node := range p.nodes {
+		node.Position = node.Add(offset)
+	}
+}
+
+// Clicked returns the node which is just clicked or touched, if any.
+func (p *BranchPanel) Clicked() (*hyper.Node, bool) {
+	positions := []Position{}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		positions = append(positions, Position{float32(x), float32(y)})
+	}
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		x, y := ebiten.TouchPosition(id)
+		positions = append(positions, Position{float32(x), float32(y)})
+	}
+	for _, pos := range positions {
+		if n, ok := p.NodeAt(pos); ok {
+			return n, true
+		}
+	}
+	return nil, false
+}
+
+// NodeAt returns the node at the position on the screen, if any.
+func (p *BranchPanel) NodeAt(pos Position) (*hyper.Node, bool) {
+	if !image.Pt(int(pos.X), int(pos.Y)).In(p.rect) {
+		return nil, false
+	}
+	for _, node := range p.nodes {
+		d := pos.Sub(node.Position)
+		if d.Len() <= BRANCH_SPACING*p.scale/2 {
+			return node.Node, true
+		}
+	}
+	return nil, false
+}
+
+// Draw renders the tree with the nodes colored as the moved actors and the active line emphasized.
+func (p *BranchPanel) Draw(screen *ebiten.Image, t *Theme) {
+	if p.rect.Empty() {
+		return
+	}
+	dst := screen.SubImage(p.rect).(*ebiten.Image)
+	palette := t.Palette()
+	r := BRANCH_SPACING * p.scale / 4
+
+	positions := map[*hyper.Node]Position{}
+	for _, node := range p.nodes {
+		positions[node.Node] = node.Position
+	}
+	for _, node := range p.nodes {
+		if node.Parent == nil {
+			continue
+		}
+		parent := positions[node.Parent]
+		clr, width := t.Grid, p.scale
+		if node.Parent.Active() == node.Node {
+			clr, width = t.Wall, 2*p.scale
+		}
+		vector.StrokeLine(dst, parent.X, parent.Y, node.X, node.Y, width, clr, true)
+	}
+	for _, node := range p.nodes {
+		if node.Record == nil {
+			vector.DrawFilledCircle(dst, node.X, node.Y, r, t.Center, true)
+		} else {
+			vector.DrawFilledCircle(dst, node.X, node.Y, r, palette.Color(node.Color), true)
+		}
+		vector.StrokeCircle(dst, node.X, node.Y, r, p.scale, t.Outline, true)
+	}
+	if p.current != nil {
+		vector.StrokeCircle(dst, p.current.X, p.current.Y, r*2, 2*p.scale, t.Outline, true)
+	}
+	vector.StrokeRect(dst, float32(p.rect.Min.X), float32(p.rect.Min.Y), float32(p.rect.Dx()), float32(p.rect.Dy()), p.scale, t.Grid, false)
+}
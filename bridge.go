@@ -0,0 +1,225 @@
+package main
+
+import (
+	"container/list"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+// Command is a request from outside of the game, which is applied on an update of the game.
+type Command func(m *StateMachine) error
+
+// BoardInfo is a snapshot of the board of the current game.
+type BoardInfo struct {
+	Code   string // puzzle code of the board, or empty if the board is not a valid puzzle
+	Steps  int
+	Goaled bool
+	Goal   hyper.Goal
+	Actors map[hyper.Color]hyper.Point
+}
+
+// Bridge connects the game to the outside such as the page of the Web version.
+// Commands can be pushed from any goroutine, and are applied at the beginning of the next update.
+// Events of the board of the current game are forwarded to subscribers on updates.
+type Bridge struct {
+	*StateMachine
+	Ready       func() // called on the first update where a game is current, or nil
+	mu          sync.Mutex
+	commands    *list.List // of Command
+	games       *list.List // of func(g *GameState) error, waiting for a game to be current
+	subscribers map[int]hyper.Subscriber
+	nextID      int
+	info        BoardInfo
+	board       *hyper.Board // board whose events are forwarded
+	unsubscribe func()
+}
+
+// NewBridge creates a Bridge of the state machine.
+func NewBridge(m *StateMachine) *Bridge {
+	return &Bridge{
+		StateMachine: m,
+		commands:     list.New(),
+		games:        list.New(),
+		subscribers:  map[int]hyper.Subscriber{},
+	}
+}
+
+// Push queues the command to be applied on the next update.
+func (b *Bridge) Push(c Command) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.commands.PushBack(c)
+}
+
+// PushGame queues the command to be applied to the current game.
+// It is kept in the queue while the current state is not a game, such as while loading.
+func (b *Bridge) PushGame(f func(g *GameState) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.games.PushBack(f)
+}
+
+// PushAction queues the action to be performed in the current game as if it were triggered by an input device.
+func (b *Bridge) PushAction(a Action) {
+	b.PushGame(func(g *GameState) error {
+		g.ControlEventDispatcher.Push(&ControlEvent{Kind: ActionEvent, Action: a})
+		return nil
+	})
+}
+
+// LoadPuzzle queues to start the puzzle of the code. The board is created immediately so that errors are returned to the caller.
+func (b *Bridge) LoadPuzzle(code string) error {
+	p, err := hyper.ParseCode(code)
+	if err != nil {
+		return err
+	}
+	board, err := p.NewBoard(DefaultPlacement)
+	if err != nil {
+		return err
+	}
+	b.PushGame(func(g *GameState) error {
+		return g.Play(board)
+	})
+	return nil
+}
+
+// LoadMap queues to start a game at random on the map of the wall bits of each cell.
+// The board is created immediately so that errors of the map and of placing actors are returned to the caller.
+func (b *Bridge) LoadMap(rows [][]int) error {
+	m, err := hyper.NewMapdataFromSlice(rows)
+	if err != nil {
+		return err
+	}
+	if m.W < hyper.MIN_BOARD_SIZE || m.H < hyper.MIN_BOARD_SIZE {
+		return fmt.Errorf("%w: %dx%d", hyper.ErrInvalidSize, m.W, m.H)
+	}
+	board, err := hyper.NewBoardOnMap(m, DefaultPlacement)
+	if err != nil {
+		return err
+	}
+	b.PushGame(func(g *GameState) error {
+		return g.Play(board)
+	})
+	return nil
+}
+
+// Info returns the snapshot of the board of the current game as of the last update.
+func (b *Bridge) Info() BoardInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.info
+}
+
+// Subscribe registers the subscriber to be notified of events of the board of the current game, even after it is replaced.
+// Subscribers are called on updates of the game.
+func (b *Bridge) Subscribe(f hyper.Subscriber) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = f
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, id)
+	}
+}
+
+// Update applies the queued commands, follows the board of the current game and updates the current state.
+// Commands to the game are applied once a game is current.
+// Errors of commands are logged instead of being returned, so that a bad request from outside does not stop the game.
+func (b *Bridge) Update() error {
+	b.mu.Lock()
+	commands := []Command{}
+	for b.commands.Len() > 0 {
+		commands = append(commands, b.commands.Remove(b.commands.Front()).(Command))
+	}
+	b.mu.Unlock()
+
+	for _, c := range commands {
+		if err := c(b.StateMachine); err != nil {
+			log.Println(err)
+		}
+	}
+	b.applyGames()
+	b.follow()
+	return b.StateMachine.Update()
+}
+
+// applyGames applies the queued commands to the game if it is current.
+func (b *Bridge) applyGames() {
+	g, ok := b.StateMachine.Current.(*GameState)
+	if !ok {
+		return
+	}
+	b.mu.Lock()
+	games := []func(g *GameState) error{}
+	for b.games.Len() > 0 {
+		games = append(games, b.games.Remove(b.games.Front()).(func(g *GameState) error))
+	}
+	b.mu.Unlock()
+
+	for _, f := range games {
+		if err := f(g); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// follow forwards events of the board of the current game, replacing the board followed so far.
+// Ready is called when the first board is followed.
+func (b *Bridge) follow() {
+	g, ok := b.StateMachine.Current.(*GameState)
+	if !ok || g.Board == b.board {
+		return
+	}
+	first := b.board == nil
+	if b.unsubscribe != nil {
+		b.unsubscribe()
+	}
+	b.board = g.Board
+	b.unsubscribe = g.Board.Subscribe(b.forward)
+	b.snapshot()
+	if first && b.Ready != nil {
+		b.Ready()
+	}
+}
+
+// forward updates the snapshot and notifies subscribers of the event.
+func (b *Bridge) forward(e *hyper.Event) {
+	b.snapshot()
+
+	// call subscribers without the lock so that they can use the bridge
+	b.mu.Lock()
+	subscribers := make([]hyper.Subscriber, 0, len(b.subscribers))
+	for _, f := range b.subscribers {
+		subscribers = append(subscribers, f)
+	}
+	b.mu.Unlock()
+	for _, f := range subscribers {
+		f(e)
+	}
+}
+
+// snapshot updates the snapshot of the followed board.
+func (b *Bridge) snapshot() {
+	info := BoardInfo{
+		Steps:  b.board.Steps(),
+		Goaled: b.board.Goaled,
+		Goal:   b.board.Goal,
+		Actors: map[hyper.Color]hyper.Point{},
+	}
+	for c, actor := range b.board.Actors {
+		info.Actors[c] = actor.Point
+	}
+	if code, err := hyper.NewPuzzle(b.board).Code(); err == nil {
+		info.Code = code
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.info = info
+}
@@ -0,0 +1,105 @@
+//go:build js
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"syscall/js"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+// BRIDGE_NAME is the name of the global object of the API for the page.
+const BRIDGE_NAME = "hyperTux"
+
+// BRIDGE_READY_EVENT is the name of the event dispatched on window when the API is ready and the game is running.
+const BRIDGE_READY_EVENT = "hypertuxready"
+
+// exposeBridge makes the bridge available to the page as window.hyperTux.
+// Functions return an error message, or null on success.
+func exposeBridge(b *Bridge) {
+	api := js.Global().Get("Object").New()
+	api.Set("loadPuzzle", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) < 1 {
+			return "loadPuzzle(code) requires a puzzle code"
+		}
+		return jsError(b.LoadPuzzle(args[0].String()))
+	}))
+	api.Set("loadMap", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) < 1 {
+			return "loadMap(rows) requires wall bits of each cell"
+		}
+		rows := [][]int{}
+		if err := fromJS(args[0], &rows); err != nil {
+			return err.Error()
+		}
+		return jsError(b.LoadMap(rows))
+	}))
+	api.Set("board", js.FuncOf(func(this js.Value, args []js.Value) any {
+		return toJS(b.Info())
+	}))
+	api.Set("steps", js.FuncOf(func(this js.Value, args []js.Value) any {
+		return b.Info().Steps
+	}))
+	api.Set("onGoalReached", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) < 1 || args[0].Type() != js.TypeFunction {
+			return "onGoalReached(callback) requires a function"
+		}
+		callback := args[0]
+		unsubscribe := b.Subscribe(func(e *hyper.Event) {
+			if e.Kind == hyper.GoalReachedEvent {
+				callback.Invoke(toJS(b.Info()))
+			}
+		})
+		// the returned function stops notifying the callback
+		var f js.Func
+		f = js.FuncOf(func(this js.Value, args []js.Value) any {
+			unsubscribe()
+			f.Release()
+			return nil
+		})
+		return f
+	}))
+	actions := map[string]Action{
+		"undo":    UndoAction,
+		"redo":    RedoAction,
+		"reset":   ResetAction,
+		"newGame": NewGameAction,
+	}
+	for name, a := range actions {
+		api.Set(name, js.FuncOf(func(this js.Value, args []js.Value) any {
+			b.PushAction(a)
+			return nil
+		}))
+	}
+
+	js.Global().Set(BRIDGE_NAME, api)
+	b.Ready = func() {
+		js.Global().Call("dispatchEvent", js.Global().Get("Event").New(BRIDGE_READY_EVENT))
+	}
+}
+
+// jsError converts the error into its message for the page, or null if there is no error.
+func jsError(err error) any {
+	if err != nil {
+		return err.Error()
+	}
+	return nil
+}
+
+// toJS converts the value into a JavaScript object through JSON.
+func toJS(v any) js.Value {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Println(err)
+		return js.Null()
+	}
+	return js.Global().Get("JSON").Call("parse", string(data))
+}
+
+// fromJS converts the JavaScript value into the Go value through JSON.
+func fromJS(v js.Value, dst any) error {
+	data := js.Global().Get("JSON").Call("stringify", v).String()
+	return json.Unmarshal([]byte(data), dst)
+}
@@ -0,0 +1,6 @@
+//go:build !js
+
+package main
+
+// exposeBridge does nothing since there is no page to expose the bridge to.
+func exposeBridge(b *Bridge) {}
@@ -5,14 +5,51 @@ import (
 
 	"github.com/ebitenui/ebitenui/image"
 	"github.com/ebitenui/ebitenui/widget"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
 )
 
+// BUTTON_WIDTH and BUTTON_HEIGHT are the size of buttons in logical pixels.
+// Labels wider than buttons are shortened so that the buttons fit in the grid of the controls.
+const (
+	BUTTON_WIDTH  = 96 // px
+	BUTTON_HEIGHT = 32 // px
+)
+
+// BUTTON_PADDING is the space around labels of buttons in logical pixels.
+const BUTTON_PADDING = 5 // px
+
+// control is a button in the controls panel of a screen.
+type control struct {
+	label   string
+	onclick widget.ButtonClickedHandlerFunc
+}
+
 // loadButtonImage creates and returns button styling images for idle, hover, and pressed states.
-func loadButtonImage() (*widget.ButtonImage, error) {
+// Images of the theme are used if specified, otherwise its colors are used.
+func loadButtonImage(r *ResourceLoader, t *ButtonTheme) (*widget.ButtonImage, error) {
+	load := func(path string, c color.Color) (*image.NineSlice, error) {
+		if path == "" {
+			return image.NewNineSliceColor(c), nil
+		}
+		img, err := r.Image(path)
+		if err != nil {
+			return nil, err
+		}
+		return image.NewNineSliceBorder(img, t.Border), nil
+	}
 
-	idle := image.NewNineSliceColor(color.NRGBA{R: 170, G: 170, B: 180, A: 255})
-	hover := image.NewNineSliceColor(color.NRGBA{R: 130, G: 130, B: 150, A: 255})
-	pressed := image.NewNineSliceColor(color.NRGBA{R: 130, G: 130, B: 150, A: 255})
+	idle, err := load(t.IdleImage, t.Idle)
+	if err != nil {
+		return nil, err
+	}
+	hover, err := load(t.HoverImage, t.Hover)
+	if err != nil {
+		return nil, err
+	}
+	pressed, err := load(t.PressedImage, t.Pressed)
+	if err != nil {
+		return nil, err
+	}
 
 	return &widget.ButtonImage{
 		Idle:    idle,
@@ -21,30 +58,39 @@ func loadButtonImage() (*widget.ButtonImage, error) {
 	}, nil
 }
 
-// createButton creates a button widget with the given label and click handler.
-func createButton(r *ResourceLoader, label string, onclick widget.ButtonClickedHandlerFunc) (*widget.Button, error) {
-	img, err := loadButtonImage()
+// createButton creates a button widget with the given label and click handler, scaled by the device scale factor.
+func createButton(r *ResourceLoader, t *ButtonTheme, scale float64, label string, onclick widget.ButtonClickedHandlerFunc) (*widget.Button, error) {
+	img, err := loadButtonImage(r, t)
 	if err != nil {
 		return nil, err
 	}
-	font, err := r.FontFace(12)
+	font, err := r.FontFace(int(12 * scale))
 	if err != nil {
 		return nil, err
 	}
+	padding := int(BUTTON_PADDING * scale)
 	b := widget.NewButton(
 		widget.ButtonOpts.WidgetOpts(
-			widget.WidgetOpts.LayoutData(widget.RowLayoutData{
-				Position: widget.RowLayoutPositionCenter,
-				Stretch:  false,
-			}),
-			widget.WidgetOpts.MinSize(76, 32),
+			widget.WidgetOpts.MinSize(int(BUTTON_WIDTH*scale), int(BUTTON_HEIGHT*scale)),
 		),
 		widget.ButtonOpts.Image(img),
-		widget.ButtonOpts.Text(label, font, &widget.ButtonTextColor{
-			Idle: color.NRGBA{R: 0, G: 0, B: 0, A: 255},
+		widget.ButtonOpts.Text(fitLabel(label, font, float64(int(BUTTON_WIDTH*scale)-2*padding)), font, &widget.ButtonTextColor{
+			Idle: t.Text,
 		}),
-		widget.ButtonOpts.TextPadding(widget.NewInsetsSimple(5)),
+		widget.ButtonOpts.TextPadding(widget.NewInsetsSimple(padding)),
 		widget.ButtonOpts.ClickedHandler(onclick),
 	)
 	return b, nil
 }
+
+// fitLabel shortens the label with an ellipsis so that it is not wider than width in the face.
+func fitLabel(label string, face text.Face, width float64) string {
+	if text.Advance(label, face) <= width {
+		return label
+	}
+	runes := []rune(label)
+	for len(runes) > 0 && text.Advance(string(runes)+"…", face) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
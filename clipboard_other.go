@@ -0,0 +1,25 @@
+//go:build !js && !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// copyToClipboard copies the text to the clipboard with the command of the desktop environment.
+func copyToClipboard(text string) error {
+	var cmd *exec.Cmd
+	switch {
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("pbcopy")
+	case os.Getenv("WAYLAND_DISPLAY") != "":
+		cmd = exec.Command("wl-copy")
+	default:
+		cmd = exec.Command("xclip", "-selection", "clipboard")
+	}
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
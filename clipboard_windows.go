@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os/exec"
+	"syscall"
+	"unicode/utf16"
+)
+
+// CREATE_NO_WINDOW keeps commands from opening a console window.
+const CREATE_NO_WINDOW = 0x08000000
+
+// copyToClipboard copies the text to the clipboard with clip, which reads UTF-16 text starting with the byte order mark.
+func copyToClipboard(text string) error {
+	buf := &bytes.Buffer{}
+	units := append([]uint16{0xfeff}, utf16.Encode([]rune(text))...)
+	if err := binary.Write(buf, binary.LittleEndian, units); err != nil {
+		return err
+	}
+	cmd := exec.Command("clip")
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CreationFlags: CREATE_NO_WINDOW}
+	cmd.Stdin = buf
+	return cmd.Run()
+}
@@ -6,36 +6,48 @@ import (
 	"github.com/fj68/hyper-tux-go/hyper"
 )
 
+// Palette maps each hyper.Color to a color on the screen.
+type Palette map[hyper.Color]color.Color
+
+// HighContrastPalette uses the Okabe-Ito colors which stay distinguishable with common color vision deficiencies.
+var HighContrastPalette = Palette{
+	hyper.Black:  color.Black,
+	hyper.Red:    color.RGBA{213, 94, 0, 255},   // vermillion
+	hyper.Green:  color.RGBA{0, 158, 115, 255},  // bluish green
+	hyper.Blue:   color.RGBA{0, 114, 178, 255},  // blue
+	hyper.Yellow: color.RGBA{240, 228, 66, 255}, // yellow
+}
+
 // Color returns the RGBA color value for a given hyper.Color.
-func Color(c hyper.Color) color.Color {
-	switch c {
-	case hyper.Black:
-		return color.Black
-	case hyper.Red:
-		return color.RGBA{255, 0, 0, 255}
-	case hyper.Green:
-		return color.RGBA{0, 255, 0, 255}
-	case hyper.Blue:
-		return color.RGBA{0, 0, 255, 255}
-	case hyper.Yellow:
-		return color.RGBA{255, 255, 0, 255}
+func (p Palette) Color(c hyper.Color) color.Color {
+	if v, ok := p[c]; ok {
+		return v
 	}
 	return color.Transparent
 }
 
-// Offset returns a visual offset value for drawing history trails of different colored actors.
-func Offset(color hyper.Color) float32 {
-	switch color {
-	case hyper.Red:
-		return 0
-	case hyper.Green:
-		return 1
-	case hyper.Blue:
-		return -1
-	case hyper.Yellow:
-		return 2
-	case hyper.Black:
-		return -2
+// Contrast returns black or white, whichever is more readable on the given color.
+func Contrast(c color.Color) color.Color {
+	g := color.GrayModel.Convert(c).(color.Gray)
+	if g.Y > 128 {
+		return color.Black
+	}
+	return color.White
+}
+
+// Mix returns the color between a and b, which is a at ratio 0 and b at ratio 1.
+func Mix(a, b color.Color, ratio float64) color.Color {
+	na := color.NRGBAModel.Convert(a).(color.NRGBA)
+	nb := color.NRGBAModel.Convert(b).(color.NRGBA)
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*ratio)
 	}
-	return 0
+	return color.NRGBA{mix(na.R, nb.R), mix(na.G, nb.G), mix(na.B, nb.B), mix(na.A, nb.A)}
+}
+
+// Translucent returns the color with the given alpha value.
+func Translucent(c color.Color, alpha uint8) color.Color {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	n.A = alpha
+	return n
 }
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"container/list"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// ControlEventKind represents the kind of a ControlEvent.
+type ControlEventKind int
+
+// ControlEventKind constants for discrete inputs.
+const (
+	SelectEvent     ControlEventKind = iota // select the actor of Color
+	SelectNextEvent                         // select the next actor
+	SelectPrevEvent                         // select the previous actor
+	MoveEvent                               // move the selected actor to Direction
+	ActionEvent                             // perform Action
+)
+
+// ControlEvent represents a discrete input such as a key press or a button press.
+// Only the fields relevant to Kind are meaningful.
+type ControlEvent struct {
+	Kind ControlEventKind
+	hyper.Color
+	hyper.Direction
+	Action
+}
+
+// ControlEventHandler is an interface for handling discrete input events.
+type ControlEventHandler interface {
+	HandleControl() []*ControlEvent
+}
+
+// ControlEventDispatcher manages and dispatches control events from multiple event handlers.
+type ControlEventDispatcher struct {
+	q             *list.List // of *ControlEvent
+	EventHandlers []ControlEventHandler
+}
+
+// NewControlEventDispatcher creates a new ControlEventDispatcher with the given event handlers.
+func NewControlEventDispatcher(handlers ...ControlEventHandler) *ControlEventDispatcher {
+	return &ControlEventDispatcher{
+		q:             list.New(),
+		EventHandlers: handlers,
+	}
+}
+
+// Update collects ControlEvents from all handlers.
+func (d *ControlEventDispatcher) Update() error {
+	for _, handler := range d.EventHandlers {
+		for _, ev := range handler.HandleControl() {
+			d.q.PushBack(ev)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of pending control events in the queue.
+func (d *ControlEventDispatcher) Len() int {
+	return d.q.Len()
+}
+
+// Push adds a control event to the queue.
+func (d *ControlEventDispatcher) Push(ev *ControlEvent) {
+	d.q.PushBack(ev)
+}
+
+// Pop removes and returns the next control event from the queue.
+func (d *ControlEventDispatcher) Pop() *ControlEvent {
+	front := d.q.Front()
+	if front == nil {
+		return nil
+	}
+	v := d.q.Remove(front)
+	ev, ok := v.(*ControlEvent)
+	if !ok {
+		return nil
+	}
+	return ev
+}
+
+// keyboardSelections maps number keys to the actor to be selected, in order of hyper.AllColors.
+var keyboardSelections = []ebiten.Key{
+	ebiten.Key1,
+	ebiten.Key2,
+	ebiten.Key3,
+	ebiten.Key4,
+	ebiten.Key5,
+}
+
+// keyboardDirections maps arrow keys and WASD to directions.
+var keyboardDirections = map[ebiten.Key]hyper.Direction{
+	ebiten.KeyArrowUp:    hyper.North,
+	ebiten.KeyArrowLeft:  hyper.West,
+	ebiten.KeyArrowRight: hyper.East,
+	ebiten.KeyArrowDown:  hyper.South,
+	ebiten.KeyW:          hyper.North,
+	ebiten.KeyA:          hyper.West,
+	ebiten.KeyD:          hyper.East,
+	ebiten.KeyS:          hyper.South,
+}
+
+// keyboardActions maps keys to actions.
+var keyboardActions = map[ebiten.Key]Action{
+	ebiten.KeyZ:            UndoAction,
+	ebiten.KeyY:            RedoAction,
+	ebiten.KeyR:            ResetAction,
+	ebiten.KeyN:            NewGameAction,
+	ebiten.KeyM:            MuteAction,
+	ebiten.KeyBracketLeft:  PrevBranchAction,
+	ebiten.KeyBracketRight: NextBranchAction,
+	ebiten.KeyE:            EditAction,
+	ebiten.KeyC:            ShareAction,
+	ebiten.KeyT:            StatsAction,
+	ebiten.KeyP:            PacksAction,
+	ebiten.KeyH:            TutorialAction,
+}
+
+// KeyboardEventHandler handles keyboard input events.
+type KeyboardEventHandler struct {
+	keys []ebiten.Key
+}
+
+// HandleControl returns ControlEvents for keys which are just pressed.
+func (h *KeyboardEventHandler) HandleControl() []*ControlEvent {
+	h.keys = inpututil.AppendJustPressedKeys(h.keys[:0])
+
+	events := []*ControlEvent{}
+	for _, key := range h.keys {
+		for i, k := range keyboardSelections {
+			if key == k && i < len(hyper.AllColors) {
+				events = append(events, &ControlEvent{Kind: SelectEvent, Color: hyper.AllColors[i]})
+			}
+		}
+		if key == ebiten.KeyTab {
+			if ebiten.IsKeyPressed(ebiten.KeyShift) {
+				events = append(events, &ControlEvent{Kind: SelectPrevEvent})
+			} else {
+				events = append(events, &ControlEvent{Kind: SelectNextEvent})
+			}
+		}
+		if d, ok := keyboardDirections[key]; ok {
+			events = append(events, &ControlEvent{Kind: MoveEvent, Direction: d})
+		}
+		if a, ok := keyboardActions[key]; ok {
+			events = append(events, &ControlEvent{Kind: ActionEvent, Action: a})
+		}
+	}
+	return events
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"time"
+
+	"github.com/ebitenui/ebitenui"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// DAILY_NAME is the name of the scores of daily puzzles in the storage.
+const DAILY_NAME = "daily.json"
+
+// DailyMode is the daily puzzle of a date played in a game.
+// Only the first attempt is scored, and the score is kept in the storage so that it is not scored again.
+type DailyMode struct {
+	Storage                   // or nil if the score is not kept
+	Date    time.Time         // date of the puzzle in UTC
+	Score   *hyper.DailyScore // score of the first attempt, or nil if the puzzle is not solved yet
+}
+
+// NewDailyBoard creates the board of the daily puzzle of the date on DefaultMap.
+func NewDailyBoard(date time.Time) (*hyper.Board, error) {
+	m, err := hyper.NewMapdataFromSlice(DefaultMap)
+	if err != nil {
+		return nil, err
+	}
+	return hyper.NewDailyBoard([]*hyper.Mapdata{m}, date, DefaultPlacement)
+}
+
+// LoadDailyMode returns the daily puzzle of the date with its score if it is kept in the storage.
+func LoadDailyMode(s Storage, date time.Time) *DailyMode {
+	d := &DailyMode{Storage: s, Date: date}
+	d.Score = d.scores()[date.Format(hyper.DAILY_DATE_FORMAT)]
+	return d
+}
+
+// scores reads the scores of daily puzzles by date from the storage.
+func (d *DailyMode) scores() map[string]*hyper.DailyScore {
+	scores := map[string]*hyper.DailyScore{}
+	if d.Storage == nil {
+		return scores
+	}
+	data, err := d.Storage.Load(DAILY_NAME)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println(err)
+		}
+		return scores
+	}
+	if err := json.Unmarshal(data, &scores); err != nil {
+		log.Println(err)
+	}
+	return scores
+}
+
+// Record scores the puzzle solved on the board, and keeps the score in the storage.
+// It does nothing if the puzzle is already scored.
+func (d *DailyMode) Record(b *hyper.Board) {
+	if d.Score != nil {
+		return
+	}
+	d.Score = hyper.NewDailyScore(d.Date, b)
+	if d.Storage == nil {
+		return
+	}
+	scores := d.scores()
+	scores[d.Score.Date] = d.Score
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(scores); err != nil {
+		log.Println(err)
+		return
+	}
+	if err := d.Storage.Store(DAILY_NAME, buf.Bytes()); err != nil {
+		log.Println(err)
+	}
+}
+
+// DailyState shows the score of the daily puzzle, and returns to the game on Back.
+type DailyState struct {
+	UI      *ebitenui.UI
+	game    *GameState // game to return to
+	score   *hyper.DailyScore
+	shared  string        // message shown after Share is pressed, or empty
+	sharing <-chan string // receives the message of the share in progress, or nil
+	width   int
+	height  int
+	scale   float64
+	resized bool // whether the UI is to be rebuilt on the next update
+}
+
+// NewDailyState creates a DailyState of the score, returning to the game which must have Machine.
+func NewDailyState(game *GameState, score *hyper.DailyScore) (*DailyState, error) {
+	s := &DailyState{
+		game:   game,
+		score:  score,
+		width:  DEFAULT_SCREEN_WIDTH,
+		height: DEFAULT_SCREEN_HEIGHT,
+		scale:  1,
+	}
+	if err := s.applyLayout(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Resize schedules to rebuild the UI for the new screen size on the next update.
+func (s *DailyState) Resize(width, height int, scale float64) {
+	if s.width != width || s.height != height || s.scale != scale {
+		s.width, s.height, s.scale = width, height, scale
+		s.resized = true
+	}
+}
+
+// applyLayout rebuilds the UI to fit the screen.
+func (s *DailyState) applyLayout() error {
+	ui, err := s.createUI()
+	if err != nil {
+		return err
+	}
+	s.UI = ui
+	return nil
+}
+
+// Update handles the UI and returns to the game on Escape.
+func (s *DailyState) Update() error {
+	if s.resized {
+		s.resized = false
+		if err := s.applyLayout(); err != nil {
+			return err
+		}
+	}
+	select {
+	case message := <-s.sharing:
+		s.shared = message
+		s.sharing = nil
+	default:
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.back()
+		return nil
+	}
+	s.UI.Update()
+	return nil
+}
+
+// back returns to the game.
+func (s *DailyState) back() {
+	s.game.Machine.Switch(s.game)
+}
+
+// Draw renders the score with a square of the color of each moved actor, and the buttons.
+func (s *DailyState) Draw(screen *ebiten.Image) {
+	t := s.game.theme()
+	screen.Fill(t.Background)
+	lineHeight := float32(24 * s.scale)
+	center := Position{float32(s.width) / 2, float32(s.height)/2 - lineHeight*3}
+	r := s.game.renderer
+	r.DrawLetter(screen, "Daily puzzle "+s.score.Date, center, float32(16*s.scale), t.Wall)
+	center.Y += lineHeight
+	r.DrawLetter(screen, s.score.Summary(), center, float32(14*s.scale), t.Wall)
+	center.Y += lineHeight
+
+	size := float32(12 * s.scale)
+	gap := float32(4 * s.scale)
+	x := center.X - (size+gap)*float32(len(s.score.Colors))/2
+	for _, c := range s.score.Colors {
+		vector.DrawFilledRect(screen, x, center.Y, size, size, t.Robots[c], false)
+		x += size + gap
+	}
+
+	// whether the share text is copied is shown below the buttons, as emoji in the text are not in the font
+	center.Y += float32(112 * s.scale)
+	r.DrawLetter(screen, s.shared, center, float32(12*s.scale), t.Wall)
+	s.UI.Draw(screen)
+}
+
+// createUI creates and returns the UI container with the buttons below the score.
+func (s *DailyState) createUI() (*ebitenui.UI, error) {
+	root := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewAnchorLayout()),
+	)
+	btnContainer := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(2),
+			widget.GridLayoutOpts.Spacing(int(8*s.scale), int(8*s.scale)),
+		)),
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
+			HorizontalPosition: widget.AnchorLayoutPositionCenter,
+			VerticalPosition:   widget.AnchorLayoutPositionCenter,
+			Padding:            widget.Insets{Top: int(64 * s.scale)},
+		})),
+	)
+	root.AddChild(btnContainer)
+
+	buttons := []struct {
+		label   string
+		onclick func()
+	}{
+		{"Share", func() {
+			s.sharing = shareText(s.score.Share())
+		}},
+		{"Back", s.back},
+	}
+	for _, b := range buttons {
+		onclick := b.onclick
+		clicked := func(args *widget.ButtonClickedEventArgs) {
+			s.game.emit(&GameEvent{Kind: ClickEvent})
+			onclick()
+		}
+		btn, err := createButton(s.game.ResourceLoader, &s.game.theme().Button, s.scale, b.label, clicked)
+		if err != nil {
+			return nil, err
+		}
+		btnContainer.AddChild(btn)
+	}
+
+	return &ebitenui.UI{
+		Container: root,
+	}, nil
+}
@@ -0,0 +1,524 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"os"
+
+	"github.com/ebitenui/ebitenui"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// EDITOR_FILE is the file which the editor saves puzzles to and loads them from.
+const EDITOR_FILE = "puzzle.json"
+
+// EDGE_MARGIN is the ratio of the cell size within which clicks are taken as clicks on edges of the cell.
+const EDGE_MARGIN = 0.25
+
+// EditorState lets players make puzzles: clicking edges of cells toggles walls, dragging actors moves them,
+// and clicking cells places the goal.
+type EditorState struct {
+	*StateMachine
+	*ControlEventDispatcher
+	*ResourceLoader
+	GameEventSource
+	UI       *ebitenui.UI
+	Audio    *AudioManager
+	Filename string       // file to save the puzzle to and load it from
+	board    *hyper.Board // puzzle being edited, whose history is not used
+	undo     []*hyper.Puzzle
+	redo     []*hyper.Puzzle
+	themes   []*Theme
+	layout   *Layout // applied layout
+	resized  *Layout // layout to be applied on the next update
+	stage    *ebiten.Image
+	renderer *Renderer
+	pointers []SwipeEventHandler
+	pointer  SwipeEventHandler // handler of the ongoing press, or nil
+	start    Position          // where the ongoing press started
+	cursor   Position
+	dragging bool        // whether an actor is being dragged
+	dragged  hyper.Color // actor being dragged
+	message  string      // result of the last command
+}
+
+// NewEditorState creates an editor of the puzzle.
+// Settings and sounds are shared with the states which the editor switches to.
+func NewEditorState(m *StateMachine, r *ResourceLoader, themes []*Theme, audio *AudioManager, p *hyper.Puzzle) (*EditorState, error) {
+	b, err := p.NewBoard(hyper.Placement{})
+	if err != nil {
+		return nil, err
+	}
+	e := &EditorState{
+		StateMachine:           m,
+		ControlEventDispatcher: NewControlEventDispatcher(&KeyboardEventHandler{}),
+		ResourceLoader:         r,
+		Audio:                  audio,
+		Filename:               EDITOR_FILE,
+		board:                  b,
+		themes:                 themes,
+		pointers:               []SwipeEventHandler{&MouseEventHandler{}, &TouchEventHandler{}},
+	}
+	e.AddListener(audio)
+	if err := e.applyLayout(NewLayout(DEFAULT_SCREEN_WIDTH, DEFAULT_SCREEN_HEIGHT, 1, b.Size, len(e.controls()))); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Resize schedules to apply the layout for the new screen size on the next update.
+// The layout is computed only when the size is changed, as it is called every frame.
+func (e *EditorState) Resize(width, height int, scale float64) {
+	pending := e.layout
+	if e.resized != nil {
+		pending = e.resized
+	}
+	if pending.Fits(width, height, scale) {
+		return
+	}
+	l := NewLayout(width, height, scale, e.board.Size, len(e.controls()))
+	if !e.layout.Equals(l) {
+		e.resized = l
+	}
+}
+
+// applyLayout rebuilds the stage and the UI to fit the layout.
+func (e *EditorState) applyLayout(l *Layout) error {
+	e.ResourceLoader.Font = e.theme().Font
+	ui, err := e.createUI(l)
+	if err != nil {
+		return err
+	}
+	if e.stage != nil {
+		e.stage.Deallocate()
+	}
+	e.stage = ebiten.NewImage(l.Stage.Dx(), l.Stage.Dy())
+	e.renderer = NewRenderer(e.ResourceLoader, e.Audio.Settings, e.theme(), l)
+	e.UI = ui
+	e.layout = l
+	return nil
+}
+
+// theme returns the theme selected in the settings.
+func (e *EditorState) theme() *Theme {
+	return SelectTheme(e.themes, e.Audio.Settings)
+}
+
+// Update handles the pointer, keys and the UI each frame.
+func (e *EditorState) Update() error {
+	if e.resized != nil {
+		if err := e.applyLayout(e.resized); err != nil {
+			return err
+		}
+		e.resized = nil
+	}
+
+	e.Audio.Update()
+
+	e.handlePointer()
+	if err := e.ControlEventDispatcher.Update(); err != nil {
+		return err
+	}
+	for e.ControlEventDispatcher.Len() > 0 {
+		ev := e.ControlEventDispatcher.Pop()
+		if ev == nil || ev.Kind != ActionEvent {
+			continue
+		}
+		if err := e.perform(ev.Action); err != nil {
+			return err
+		}
+	}
+
+	e.UI.Update()
+
+	return nil
+}
+
+// perform applies the action to the puzzle. Actions irrelevant to editing are ignored.
+func (e *EditorState) perform(a Action) error {
+	switch a {
+	case UndoAction:
+		e.Undo()
+	case RedoAction:
+		e.Redo()
+	case EditAction:
+		return e.play()
+	}
+	return nil
+}
+
+// handlePointer drags actors, or applies clicks on the stage when the pointer is released.
+func (e *EditorState) handlePointer() {
+	if e.pointer == nil {
+		for _, h := range e.pointers {
+			if pos := h.HandlePressed(); pos != nil {
+				e.pointer, e.start, e.cursor = h, *pos, *pos
+				if actor, ok := e.board.ActorAt(e.layout.ToPoint(*pos)); ok {
+					e.dragging, e.dragged = true, actor.Color
+				}
+				break
+			}
+		}
+		return
+	}
+
+	if pos := e.pointer.HandleDragging(); pos != nil {
+		e.cursor = *pos
+	}
+	pos := e.pointer.HandleReleased()
+	if pos == nil {
+		return
+	}
+	e.pointer, e.cursor = nil, *pos
+	if e.dragging {
+		e.dragging = false
+		e.moveActor(e.dragged, e.layout.ToPoint(*pos))
+		return
+	}
+	// dragging from an empty cell does nothing
+	start := e.layout.ToPoint(e.start)
+	if start.Equals(e.layout.ToPoint(*pos)) {
+		e.click(*pos)
+	}
+}
+
+// contains returns true if the cell is on the board.
+func (e *EditorState) contains(p hyper.Point) bool {
+	r := hyper.NewRect(hyper.Point{}, e.board.Size)
+	return r.Contains(p)
+}
+
+// edgeAt returns the wall on the edge of the cell nearest to the position on the screen
+// if the position is within EDGE_MARGIN from the edge.
+// The wall is on the north edge of the cell if horizontal, otherwise on the west edge.
+// Edges on the borders of the board and walls of the center box are not editable.
+func (e *EditorState) edgeAt(pos Position) (p hyper.Point, horizontal bool, ok bool) {
+	cellSize := e.layout.CellSize
+	cell := e.layout.ToPoint(pos)
+	if !e.contains(cell) {
+		return
+	}
+	local := pos.Sub(Position{float32(e.layout.Stage.Min.X), float32(e.layout.Stage.Min.Y)})
+	local = local.Sub(NewPosition(cell, cellSize))
+	edges := []struct {
+		distance   float32
+		p          hyper.Point
+		horizontal bool
+	}{
+		{local.Y, cell, true}, // north
+		{cellSize - local.Y, cell.Add(hyper.Point{X: 0, Y: 1}), true}, // south
+		{local.X, cell, false}, // west
+		{cellSize - local.X, cell.Add(hyper.Point{X: 1, Y: 0}), false}, // east
+	}
+	nearest := edges[0]
+	for _, edge := range edges[1:] {
+		if edge.distance < nearest.distance {
+			nearest = edge
+		}
+	}
+	if nearest.distance > cellSize*EDGE_MARGIN {
+		return
+	}
+
+	p, horizontal = nearest.p, nearest.horizontal
+	if horizontal {
+		ok = 0 < p.Y && p.Y < e.board.H && !e.board.IsCenterHWall(p)
+	} else {
+		ok = 0 < p.X && p.X < e.board.W && !e.board.IsCenterVWall(p)
+	}
+	return
+}
+
+// click toggles the wall on the edge near the position, or places the goal on the cell at the position.
+func (e *EditorState) click(pos Position) {
+	if p, horizontal, ok := e.edgeAt(pos); ok {
+		e.toggleWall(p, horizontal)
+		return
+	}
+	p := e.layout.ToPoint(pos)
+	if !e.contains(p) {
+		return
+	}
+	if e.board.SomethingExists(p) {
+		e.reject()
+		return
+	}
+	e.record()
+	e.board.Goal.Point = p
+}
+
+// toggleWall puts the wall on the north or west edge of the cell, or removes it if exists.
+func (e *EditorState) toggleWall(p hyper.Point, horizontal bool) {
+	before := hyper.NewPuzzle(e.board)
+	toggle := e.board.ToggleVWall
+	if horizontal {
+		toggle = e.board.ToggleHWall
+	}
+	if _, err := toggle(p); err != nil {
+		e.report(err, "")
+		e.reject()
+		return
+	}
+	e.push(before)
+}
+
+// moveActor places the actor on the cell if it is empty.
+func (e *EditorState) moveActor(c hyper.Color, p hyper.Point) {
+	actor := e.board.Actors[c]
+	if actor.Point.Equals(p) {
+		return
+	}
+	if !e.contains(p) || e.board.SomethingExists(p) {
+		e.reject()
+		return
+	}
+	e.record()
+	actor.MoveTo(p)
+}
+
+// nextGoalColor changes the color of the goal to the next one in hyper.AllColors.
+func (e *EditorState) nextGoalColor() {
+	e.record()
+	for i, c := range hyper.AllColors {
+		if c == e.board.Goal.Color {
+			e.board.Goal.Color = hyper.AllColors[(i+1)%len(hyper.AllColors)]
+			break
+		}
+	}
+	e.resized = e.layout
+}
+
+// reject notifies the player that the edit is not allowed.
+func (e *EditorState) reject() {
+	e.emit(&GameEvent{Kind: BumpEvent})
+}
+
+// record saves the puzzle before an edit so that the edit can be undone.
+func (e *EditorState) record() {
+	e.push(hyper.NewPuzzle(e.board))
+}
+
+// push saves the puzzle before an edit which has been made.
+func (e *EditorState) push(before *hyper.Puzzle) {
+	e.undo = append(e.undo, before)
+	e.redo = e.redo[:0]
+	e.message = ""
+}
+
+// restore replaces the puzzle being edited, saving the current one in the other stack.
+func (e *EditorState) restore(from *[]*hyper.Puzzle, to *[]*hyper.Puzzle) {
+	if len(*from) == 0 {
+		return
+	}
+	p := (*from)[len(*from)-1]
+	b, err := p.NewBoard(hyper.Placement{})
+	if err != nil {
+		e.message = err.Error()
+		return
+	}
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, hyper.NewPuzzle(e.board))
+	e.setBoard(b)
+}
+
+// setBoard replaces the puzzle being edited, following the size of the new board.
+func (e *EditorState) setBoard(b *hyper.Board) {
+	e.board = b
+	e.resized = NewLayout(e.layout.Width, e.layout.Height, e.layout.Scale, b.Size, len(e.controls()))
+}
+
+// Undo reverts the last edit.
+func (e *EditorState) Undo() {
+	e.restore(&e.undo, &e.redo)
+}
+
+// Redo reapplies the last undone edit.
+func (e *EditorState) Redo() {
+	e.restore(&e.redo, &e.undo)
+}
+
+// play switches to a game of the puzzle, which returns to the editor on EditAction.
+func (e *EditorState) play() error {
+	b, err := hyper.NewPuzzle(e.board).NewBoard(DefaultPlacement)
+	if err != nil {
+		e.message = err.Error()
+		return nil
+	}
+	g, err := NewGameStateFromBoard(b, e.ResourceLoader, e.themes, e.Audio.Settings)
+	if err != nil {
+		return err
+	}
+	g.Audio.Backend = e.Audio.Backend
+	g.Machine = e.StateMachine
+	g.editor = e
+	e.StateMachine.Switch(g)
+	return nil
+}
+
+// Save writes the puzzle to Filename.
+func (e *EditorState) Save() error {
+	f, err := os.Create(e.Filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return hyper.NewPuzzle(e.board).Write(f)
+}
+
+// Load reads the puzzle from Filename, which can be undone.
+func (e *EditorState) Load() error {
+	f, err := os.Open(e.Filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	p, err := hyper.ReadPuzzle(f)
+	if err != nil {
+		return fmt.Errorf("error in %s: %w", e.Filename, err)
+	}
+	b, err := p.NewBoard(hyper.Placement{})
+	if err != nil {
+		return err
+	}
+	e.record()
+	e.setBoard(b)
+	return nil
+}
+
+// report shows the result of the command.
+func (e *EditorState) report(err error, done string) {
+	if err != nil {
+		e.message = err.Error()
+		return
+	}
+	e.message = done
+}
+
+// Draw renders the puzzle being edited, the UI and the message.
+func (e *EditorState) Draw(screen *ebiten.Image) {
+	t := e.theme()
+	screen.Fill(t.Background)
+
+	e.stage.Fill(t.Background)
+	e.renderer.DrawBoard(e.stage, e.board.Mapdata)
+	e.renderer.DrawGoal(e.stage, e.board.Goal)
+	e.drawActors(e.stage)
+	e.drawEdge(e.stage)
+	e.renderer.DrawBorder(e.stage)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(e.layout.Stage.Min.X), float64(e.layout.Stage.Min.Y))
+	screen.DrawImage(e.stage, op)
+
+	e.UI.Draw(screen)
+
+	if e.message != "" {
+		r := e.layout.History
+		center := Position{float32(r.Min.X+r.Max.X) / 2, float32(r.Min.Y+r.Max.Y) / 2}
+		e.renderer.DrawLetter(screen, e.message, center, e.layout.Px(14), t.Wall)
+	}
+}
+
+// drawActors renders actors at their cells, and the dragged one under the pointer.
+func (e *EditorState) drawActors(screen *ebiten.Image) {
+	cellSize := e.layout.CellSize
+	for _, actor := range e.board.Actors {
+		p := NewPosition(actor.Point, cellSize)
+		if e.dragging && actor.Color == e.dragged {
+			// keep the offset from the pointer to the cell where the drag started
+			offset := e.cursor.Sub(e.start)
+			p = p.Add(offset)
+		}
+		e.renderer.DrawActor(screen, actor.Color, p, e.dragging && actor.Color == e.dragged)
+	}
+}
+
+// drawEdge highlights the edge which would be toggled by clicking at the cursor.
+func (e *EditorState) drawEdge(screen *ebiten.Image) {
+	if e.dragging {
+		return
+	}
+	x, y := ebiten.CursorPosition()
+	p, horizontal, ok := e.edgeAt(Position{float32(x), float32(y)})
+	if !ok {
+		return
+	}
+	cellSize := e.layout.CellSize
+	from := NewPosition(p, cellSize)
+	to := from.Add(Position{0, cellSize})
+	if horizontal {
+		to = from.Add(Position{cellSize, 0})
+	}
+	vector.StrokeLine(screen, from.X, from.Y, to.X, to.Y, e.layout.Px(3), Translucent(e.theme().Wall, 96), false)
+}
+
+// createUI creates and returns the UI container with the editor commands placed in the controls area of the layout.
+func (e *EditorState) createUI(l *Layout) (*ebitenui.UI, error) {
+	root := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewAnchorLayout()),
+	)
+	area := func(r image.Rectangle) widget.Insets {
+		return widget.Insets{
+			Top:    r.Min.Y,
+			Left:   r.Min.X,
+			Right:  l.Width - r.Max.X,
+			Bottom: l.Height - r.Max.Y,
+		}
+	}
+
+	btnContainer := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(l.Columns),
+			widget.GridLayoutOpts.Padding(widget.NewInsetsSimple(int(l.Px(CONTROLS_PADDING)))),
+			widget.GridLayoutOpts.Spacing(int(l.Px(CONTROLS_PADDING)), int(l.Px(CONTROLS_PADDING))),
+		)),
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
+			HorizontalPosition: widget.AnchorLayoutPositionCenter,
+			VerticalPosition:   widget.AnchorLayoutPositionCenter,
+			Padding:            area(l.Controls),
+		})),
+	)
+	root.AddChild(btnContainer)
+
+	for _, b := range e.controls() {
+		onclick := b.onclick
+		clicked := func(args *widget.ButtonClickedEventArgs) {
+			e.emit(&GameEvent{Kind: ClickEvent})
+			onclick(args)
+		}
+		btn, err := createButton(e.ResourceLoader, &e.theme().Button, l.Scale, b.label, clicked)
+		if err != nil {
+			return nil, err
+		}
+		btnContainer.AddChild(btn)
+	}
+
+	return &ebitenui.UI{
+		Container: root,
+	}, nil
+}
+
+// controls returns the buttons of the editor commands.
+func (e *EditorState) controls() []control {
+	action := func(a Action) widget.ButtonClickedHandlerFunc {
+		return func(args *widget.ButtonClickedEventArgs) {
+			e.ControlEventDispatcher.Push(&ControlEvent{Kind: ActionEvent, Action: a})
+		}
+	}
+	return []control{
+		{"Undo", action(UndoAction)},
+		{"Redo", action(RedoAction)},
+		{"Goal: " + e.board.Goal.Color.String(), func(args *widget.ButtonClickedEventArgs) {
+			e.nextGoalColor()
+		}},
+		{"Play", action(EditAction)},
+		{"Save", func(args *widget.ButtonClickedEventArgs) {
+			e.report(e.Save(), "Saved to "+e.Filename)
+		}},
+		{"Load", func(args *widget.ButtonClickedEventArgs) {
+			e.report(e.Load(), "Loaded "+e.Filename)
+		}},
+	}
+}
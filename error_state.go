@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// ErrorState shows an error which prevents the game from continuing.
+type ErrorState struct {
+	err error
+}
+
+// NewErrorState creates and returns an ErrorState for the error.
+func NewErrorState(err error) *ErrorState {
+	log.Println(err)
+	return &ErrorState{err}
+}
+
+// Update does nothing.
+func (s *ErrorState) Update() error {
+	return nil
+}
+
+// Draw renders the error message.
+func (s *ErrorState) Draw(screen *ebiten.Image) {
+	screen.Fill(DefaultTheme.Background)
+	ebitenutil.DebugPrint(screen, fmt.Sprintf("Something went wrong:\n%v", s.err))
+}
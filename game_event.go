@@ -0,0 +1,38 @@
+package main
+
+// GameEventKind represents the kind of a GameEvent.
+type GameEventKind int
+
+// GameEventKind constants for results of player operations which do not change the board.
+// Changes of the board are notified by hyper.Board.Subscribe.
+const (
+	BumpEvent  GameEventKind = iota // an actor was unable to move
+	ClickEvent                      // a button was clicked
+)
+
+// GameEvent notifies listeners of what happened in the game.
+type GameEvent struct {
+	Kind GameEventKind
+}
+
+// GameEventListener is an interface for receiving GameEvents, e.g. to play sounds.
+type GameEventListener interface {
+	HandleGameEvent(e *GameEvent)
+}
+
+// GameEventSource notifies its listeners of GameEvents, and is embedded in states which emit them.
+type GameEventSource struct {
+	listeners []GameEventListener
+}
+
+// AddListener registers the listener to be notified of GameEvents.
+func (s *GameEventSource) AddListener(l GameEventListener) {
+	s.listeners = append(s.listeners, l)
+}
+
+// emit notifies all listeners of the event.
+func (s *GameEventSource) emit(e *GameEvent) {
+	for _, l := range s.listeners {
+		l.HandleGameEvent(e)
+	}
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"container/list"
+	"errors"
+	"fmt"
+	"image"
 	"image/color"
 	"log"
+	"strconv"
+	"time"
 
 	"github.com/ebitenui/ebitenui"
 	"github.com/ebitenui/ebitenui/widget"
@@ -11,60 +17,158 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
+// DefaultPlacement places actors at random and goals near walls.
+var DefaultPlacement = hyper.Placement{
+	Actor: hyper.PlaceActorAtRandom,
+	Goal:  hyper.PlaceGoalNearByWalls,
+}
+
+// NOTICE_DURATION is how long a notice is shown over the stage.
+const NOTICE_DURATION = 5 * time.Second
+
 // GameState manages the main game logic including board, input, UI, and rendering.
 type GameState struct {
 	*hyper.Board
 	*SwipeEventDispatcher
+	*ControlEventDispatcher
 	*ResourceLoader
-	UI       *ebitenui.UI
-	stage    *ebiten.Image
-	controls *ebiten.Image
+	GameEventSource
+	UI        *ebitenui.UI
+	Settings  *Settings
+	Audio     *AudioManager
+	Machine   *StateMachine // to switch to the editor, or nil
+	Profile   *Profile      // stats of the player, or nil if they are not recorded
+	Storage   Storage       // to keep scores of daily puzzles, or nil
+	Daily     *DailyMode    // daily puzzle played in this game, or nil
+	Pack      *PackRun      // puzzle of a pack played in this game, or nil
+	Locked    bool          // whether only moves and settings are allowed, ignoring actions on the history and switching states
+	finished  bool          // whether the daily puzzle or the puzzle of a pack is just solved, and the result is to be shown
+	editor    *EditorState  // editor to return to, which started the test play
+	themes    []*Theme
+	animator  *Animator
+	layout    *Layout // applied layout
+	resized   *Layout // layout to be applied on the next update
+	stage     *ebiten.Image
+	renderer  *Renderer
+	branches  BranchPanel
+	history   *widget.List
+	refreshed *list.List    // of *hyper.Node selected by refreshHistory, whose deferred selection events are yet to be handled
+	selected  hyper.Color   // actor to be moved by ControlEvents
+	selecting bool          // whether the selected actor is highlighted
+	notice    string        // message shown over the stage, or empty
+	noticed   time.Time     // when the notice is shown
+	sharing   <-chan string // receives the message of the share in progress, or nil
 }
 
-// NewGameState creates and initializes a new GameState with the given board size.
+// NewGameState creates and initializes a new GameState with the given board size, loading assets synchronously.
 func NewGameState(size hyper.Size) (*GameState, error) {
-	b, err := hyper.NewBoard(size, hyper.Placement{
-		Actor: hyper.PlaceActorAtRandom,
-		Goal: hyper.PlaceGoalNearByWalls,
-	})
-	if err != nil {
-		return nil, err
-	}
-	// debug
-	b.Actors[hyper.Black].Point = hyper.Point{X: 0, Y: 0}
-	for _, actor := range b.Actors {
-		log.Println(actor)
-	}
+	r := NewResourceLoader(DefaultAssets()...)
+	return NewGameStateWithAssets(size, r, LoadThemes(r, THEMES_DIR))
+}
 
-	r := NewResourceLoader()
-	ui, err := createUI(r, b)
+// NewGameStateWithAssets creates and initializes a new GameState with the given board size and preloaded assets.
+func NewGameStateWithAssets(size hyper.Size, r *ResourceLoader, themes []*Theme) (*GameState, error) {
+	b, err := hyper.NewBoard(size, DefaultPlacement)
 	if err != nil {
 		return nil, err
 	}
+	return NewGameStateFromBoard(b, r, themes, NewSettings())
+}
 
-	stageWidth := b.W * int(CELL_SIZE)
-	stageHeight := b.H * int(CELL_SIZE)
-	stage := ebiten.NewImage(stageWidth, stageHeight)
-	controls := ebiten.NewImage(stageWidth, CONTROLS_HEIGHT)
-
-	return &GameState{
+// NewGameStateFromBoard creates and initializes a new GameState playing the board with the settings and preloaded assets.
+func NewGameStateFromBoard(b *hyper.Board, r *ResourceLoader, themes []*Theme, settings *Settings) (*GameState, error) {
+	controlEventDispatcher := NewControlEventDispatcher(
+		&KeyboardEventHandler{},
+		&GamepadEventHandler{},
+	)
+	g := &GameState{
 		Board: b,
 		SwipeEventDispatcher: NewSwipeEventDispatcher(
 			&MouseEventHandler{},
 			&TouchEventHandler{},
 		),
-		ResourceLoader: r,
-		UI:             ui,
-		stage:          stage,
-		controls:       controls,
-	}, nil
+		ControlEventDispatcher: controlEventDispatcher,
+		ResourceLoader:         r,
+		Settings:               settings,
+		themes:                 themes,
+		animator:               NewAnimator(settings),
+		Audio:                  NewAudioManager(settings, NopAudioBackend{}),
+		refreshed:              list.New(),
+	}
+	g.AddListener(g.Audio)
+	b.Subscribe(g.handleBoardEvent)
+	b.Subscribe(func(e *hyper.Event) {
+		g.refreshHistory()
+	})
+	b.Subscribe(g.Audio.HandleBoardEvent)
+	if err := g.applyLayout(NewLayout(DEFAULT_SCREEN_WIDTH, DEFAULT_SCREEN_HEIGHT, 1, b.Size, len(g.controls()))); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Resize schedules to apply the layout for the new screen size on the next update.
+// The layout is computed only when the size is changed, as it is called every frame.
+func (g *GameState) Resize(width, height int, scale float64) {
+	pending := g.layout
+	if g.resized != nil {
+		pending = g.resized
+	}
+	if pending.Fits(width, height, scale) {
+		return
+	}
+	l := NewLayout(width, height, scale, g.Board.Size, len(g.controls()))
+	if !g.layout.Equals(l) {
+		g.resized = l
+	}
 }
 
-// handleInput processes swipe events and applies actor movements to the board.
+// applyLayout rebuilds the stage and the UI to fit the layout.
+func (g *GameState) applyLayout(l *Layout) error {
+	g.ResourceLoader.Font = g.theme().Font
+	ui, err := g.createUI(l)
+	if err != nil {
+		return err
+	}
+	if g.stage != nil {
+		g.stage.Deallocate()
+	}
+	g.stage = ebiten.NewImage(l.Stage.Dx(), l.Stage.Dy())
+	g.renderer = NewRenderer(g.ResourceLoader, g.Settings, g.theme(), l)
+	g.UI = ui
+	g.layout = l
+	g.SwipeEventDispatcher.Layout = l
+	return nil
+}
+
+// handleInput processes swipe and control events and applies actor movements to the board.
+// Events are kept in the queues while an actor is sliding.
 func (g *GameState) handleInput() error {
 	if err := g.SwipeEventDispatcher.Update(); err != nil {
 		return err
 	}
+	if err := g.ControlEventDispatcher.Update(); err != nil {
+		return err
+	}
+
+	if g.animator.Animating() {
+		return nil
+	}
+
+	// control events are converted into swipe events, so handle them first
+	for g.ControlEventDispatcher.Len() > 0 {
+		e := g.ControlEventDispatcher.Pop()
+		if e == nil {
+			continue
+		}
+		if err := g.handleControl(e); err != nil {
+			return err
+		}
+		if g.animator.Animating() {
+			return nil
+		}
+	}
 
 	for g.SwipeEventDispatcher.Len() > 0 {
 		e := g.SwipeEventDispatcher.Pop()
@@ -72,27 +176,330 @@ func (g *GameState) handleInput() error {
 			continue
 		}
 		if actor, ok := g.Board.ActorAt(e.Start); ok {
-			g.Board.MoveActor(actor, e.Direction())
+			g.selected = actor.Color
+			g.moveActor(actor, e.Direction())
+		}
+		if g.animator.Animating() {
+			return nil
 		}
 	}
 
 	return nil
 }
 
+// edit switches to the editor of the puzzle of the board, or returns to the editor which started the test play.
+func (g *GameState) edit() error {
+	if g.Machine == nil {
+		return nil
+	}
+	if g.editor == nil {
+		e, err := NewEditorState(g.Machine, g.ResourceLoader, g.themes, g.Audio, hyper.NewPuzzle(g.Board))
+		if err != nil {
+			// the board may not be a valid puzzle, which should not end the game
+			log.Println(err)
+			return nil
+		}
+		g.editor = e
+	}
+	g.Machine.Switch(g.editor)
+	return nil
+}
+
+// Play switches to a new game of the board, sharing the settings, the sounds and Machine with this game.
+func (g *GameState) Play(b *hyper.Board) error {
+	_, err := g.play(b)
+	return err
+}
+
+// play switches to a new game of the board like Play, and returns it.
+func (g *GameState) play(b *hyper.Board) (*GameState, error) {
+	next, err := g.derive(b)
+	if err != nil {
+		return nil, err
+	}
+	g.Machine.Switch(next)
+	return next, nil
+}
+
+// derive creates a new game of the board sharing the settings, the sounds, Machine and the records with this game.
+func (g *GameState) derive(b *hyper.Board) (*GameState, error) {
+	if g.Machine == nil {
+		return nil, errors.New("unable to switch games without Machine")
+	}
+	next, err := NewGameStateFromBoard(b, g.ResourceLoader, g.themes, g.Settings)
+	if err != nil {
+		return nil, err
+	}
+	next.Audio.Backend = g.Audio.Backend
+	next.Machine = g.Machine
+	next.Profile = g.Profile
+	next.Storage = g.Storage
+	return next, nil
+}
+
+// jumpTo jumps to the node in the history tree clicked by the player, or bumps if the history is locked.
+func (g *GameState) jumpTo(n *hyper.Node) {
+	if g.historyLocked() {
+		g.emit(&GameEvent{Kind: BumpEvent})
+		return
+	}
+	g.Board.JumpTo(n)
+}
+
+// historyLocked reports whether moves cannot be taken back, that is, the game is locked,
+// or the daily puzzle is played and not scored yet, which would allow trying several lines in one attempt.
+func (g *GameState) historyLocked() bool {
+	return g.Locked || (g.Daily != nil && g.Daily.Score == nil)
+}
+
+// playDaily switches to the daily puzzle of today, or shows its score if it is already solved.
+func (g *GameState) playDaily() error {
+	if g.Machine == nil {
+		return nil
+	}
+	d := LoadDailyMode(g.Storage, time.Now().UTC())
+	if d.Score != nil {
+		return g.showDaily(d.Score)
+	}
+	b, err := NewDailyBoard(d.Date)
+	if err != nil {
+		return err
+	}
+	next, err := g.play(b)
+	if err != nil {
+		return err
+	}
+	next.Daily = d
+	return nil
+}
+
+// playRandom switches to a random game on the map of the board.
+func (g *GameState) playRandom() error {
+	b, err := hyper.NewBoardOnMap(g.Board.Mapdata.Clone(), DefaultPlacement)
+	if err != nil {
+		return err
+	}
+	return g.Play(b)
+}
+
+// finish shows the result of the daily puzzle, or returns to the browser of the pack of the puzzle.
+func (g *GameState) finish() error {
+	if g.Pack != nil {
+		g.Machine.Switch(g.Pack.Browser)
+		return nil
+	}
+	return g.showDaily(g.Daily.Score)
+}
+
+// showPacks switches to the browser of the puzzle packs in the assets.
+func (g *GameState) showPacks() error {
+	if g.Machine == nil {
+		return nil
+	}
+	s, err := NewPackState(g, LoadPacks(g.ResourceLoader, PACKS_DIR), g.Storage)
+	if err != nil {
+		return err
+	}
+	g.Machine.Switch(s)
+	return nil
+}
+
+// showTutorial switches to the tutorial in the assets.
+func (g *GameState) showTutorial() error {
+	if g.Machine == nil {
+		return nil
+	}
+	t, err := LoadTutorial(g.ResourceLoader)
+	if err != nil {
+		// the game goes on without the tutorial
+		log.Println(err)
+		return nil
+	}
+	s, err := NewTutorialState(g, t)
+	if err != nil {
+		return err
+	}
+	g.Machine.Switch(s)
+	return nil
+}
+
+// showDaily switches to the screen of the score of the daily puzzle.
+func (g *GameState) showDaily(score *hyper.DailyScore) error {
+	s, err := NewDailyState(g, score)
+	if err != nil {
+		return err
+	}
+	g.Machine.Switch(s)
+	return nil
+}
+
+// showStats switches to the screen of the stats of the player.
+func (g *GameState) showStats() error {
+	if g.Machine == nil || g.Profile == nil {
+		return nil
+	}
+	s, err := NewStatsState(g, g.Profile.Stats)
+	if err != nil {
+		return err
+	}
+	g.Machine.Switch(s)
+	return nil
+}
+
+// share publishes the code of the puzzle of the board.
+func (g *GameState) share() {
+	code, err := hyper.NewPuzzle(g.Board).Code()
+	if err != nil {
+		log.Println(err)
+		g.notify("Unable to share this puzzle")
+		return
+	}
+	g.sharing = sharePuzzle(code)
+}
+
+// notify shows the message over the stage for NOTICE_DURATION.
+func (g *GameState) notify(message string) {
+	g.notice = message
+	g.noticed = time.Now()
+}
+
+// moveActor moves the actor on the board, notifying listeners if it is unable to move.
+func (g *GameState) moveActor(actor *hyper.Actor, d hyper.Direction) {
+	if _, ok := g.Board.MoveActor(actor, d); !ok {
+		g.emit(&GameEvent{Kind: BumpEvent})
+	}
+}
+
+// handleBoardEvent slides actors moved on the board.
+func (g *GameState) handleBoardEvent(e *hyper.Event) {
+	switch e.Kind {
+	case hyper.MovedEvent, hyper.RedoneEvent:
+		g.animator.Start(e.Color, e.Start, e.End)
+	case hyper.UndoneEvent:
+		g.animator.Start(e.Color, e.End, e.Start)
+	case hyper.ResetEvent, hyper.NewGameEvent, hyper.JumpedEvent:
+		g.animator.Stop()
+	case hyper.GoalReachedEvent:
+		if g.Daily != nil && g.Daily.Score == nil {
+			g.Daily.Record(g.Board)
+			g.finished = true
+		}
+		if g.Pack != nil {
+			g.Pack.Browser.Record(g.Pack.Index, g.Board.Steps())
+			g.finished = true
+		}
+	}
+}
+
+// handleControl applies a single control event.
+// Moves are pushed to SwipeEventDispatcher so that they follow the same pipeline as swipes.
+func (g *GameState) handleControl(e *ControlEvent) error {
+	switch e.Kind {
+	case SelectEvent:
+		g.selectActor(e.Color)
+	case SelectNextEvent:
+		g.selectActor(g.neighborColor(1))
+	case SelectPrevEvent:
+		g.selectActor(g.neighborColor(-1))
+	case MoveEvent:
+		actor, ok := g.Board.Actors[g.selected]
+		if !ok {
+			return nil
+		}
+		g.selecting = true
+		g.SwipeEventDispatcher.Push(&SwipeEvent{
+			Start: actor.Point,
+			End:   actor.Point.Add(e.Direction.Vector()),
+		})
+	case ActionEvent:
+		return g.perform(e.Action)
+	}
+	return nil
+}
+
+// selectActor selects the actor of the given color and highlights it.
+func (g *GameState) selectActor(c hyper.Color) {
+	g.selected = c
+	g.selecting = true
+}
+
+// neighborColor returns the color next to the selected one in hyper.AllColors, wrapping around.
+func (g *GameState) neighborColor(step int) hyper.Color {
+	n := len(hyper.AllColors)
+	for i, c := range hyper.AllColors {
+		if c == g.selected {
+			return hyper.AllColors[((i+step)%n+n)%n]
+		}
+	}
+	return hyper.AllColors[0]
+}
+
 // Update updates the game state each frame, handling input and UI updates.
 func (g *GameState) Update() error {
+	if g.resized != nil {
+		if err := g.applyLayout(g.resized); err != nil {
+			return err
+		}
+		g.resized = nil
+	}
+
+	g.animator.Update()
+	g.Audio.Update()
+
 	if err := g.handleInput(); err != nil {
 		return err
 	}
 
+	// the branches are hidden and the list of moves is disabled while the history is locked, as moves could be jumped to
+	locked := g.historyLocked()
+	g.history.GetWidget().Disabled = locked
+	if !locked {
+		if n, ok := g.branches.Clicked(); ok {
+			g.jumpTo(n)
+		}
+		g.branches.Update(g.Board.HistoryTree(), g.Board.CurrentMove(), g.layout.Branches, g.layout.Scale)
+	}
+
 	g.UI.Update()
 
+	select {
+	case message := <-g.sharing:
+		g.notify(message)
+		g.sharing = nil
+	default:
+	}
+	if g.notice != "" && time.Since(g.noticed) >= NOTICE_DURATION {
+		g.notice = ""
+	}
+
+	// show the result after the last move is animated
+	if g.finished && !g.animator.Animating() {
+		g.finished = false
+		return g.finish()
+	}
+
 	return nil
 }
 
-// clear fills the screen with white.
+// theme returns the theme selected in the settings, or the first theme if it is not found.
+func (g *GameState) theme() *Theme {
+	return SelectTheme(g.themes, g.Settings)
+}
+
+// nextTheme selects the theme following the current one and rebuilds the UI with it.
+func (g *GameState) nextTheme() {
+	current := FindTheme(g.themes, g.Settings.Theme)
+	for i, t := range g.themes {
+		if t == current {
+			g.Settings.Theme = g.themes[(i+1)%len(g.themes)].Name
+		}
+	}
+	g.resized = g.layout
+}
+
+// clear fills the screen with the background color.
 func (g *GameState) clear(screen *ebiten.Image) {
-	screen.Fill(color.White)
+	screen.Fill(g.theme().Background)
 }
 
 // Draw renders the game board, actors, UI, and other visual elements.
@@ -100,49 +507,49 @@ func (g *GameState) Draw(screen *ebiten.Image) {
 	g.clear(screen)
 
 	g.drawStage(g.stage)
-	screen.DrawImage(g.stage, &ebiten.DrawImageOptions{})
+	stageOp := &ebiten.DrawImageOptions{}
+	stageOp.GeoM.Translate(float64(g.layout.Stage.Min.X), float64(g.layout.Stage.Min.Y))
+	screen.DrawImage(g.stage, stageOp)
+
+	if !g.historyLocked() {
+		g.branches.Draw(screen, g.theme())
+	}
+	g.drawNotice(screen)
+	g.drawUI(screen)
+}
 
-	g.drawUI(g.controls)
-	controlsOp := &ebiten.DrawImageOptions{}
-	controlsOp.GeoM.Translate(0, float64(g.stage.Bounds().Dy()))
-	screen.DrawImage(g.controls, controlsOp)
+// drawNotice renders the notice wrapped into lines in a band at the bottom of the stage, if any.
+func (g *GameState) drawNotice(screen *ebiten.Image) {
+	if g.notice == "" {
+		return
+	}
+	t := g.theme()
+	stage := g.layout.Stage
+	size := g.layout.Px(12)
+	lineHeight := size * 3 / 2
+	// glyphs of the font are about half as wide as they are high
+	lines := wrapText(g.notice, int(float32(stage.Dx())/(size*0.55)))
+	height := lineHeight*float32(len(lines)) + size
+	top := float32(stage.Max.Y) - height
+	vector.DrawFilledRect(screen, float32(stage.Min.X), top, float32(stage.Dx()), height, Translucent(t.Background, 224), false)
+	y := top + size/2 + lineHeight/2
+	for _, line := range lines {
+		g.renderer.DrawLetter(screen, line, Position{float32(stage.Min.X+stage.Max.X) / 2, y}, size, t.Wall)
+		y += lineHeight
+	}
 }
 
 // drawStage renders the game board and all game elements on the stage.
 func (g *GameState) drawStage(screen *ebiten.Image) {
 	g.clear(g.stage)
-	g.drawBoard(screen)
+	g.renderer.DrawBoard(screen, g.Board.Mapdata)
+	g.drawAnalysis(screen)
+	g.drawReach(screen)
 	g.drawActors(screen)
 	g.drawHistory(screen)
-	g.drawGoal(screen)
-	// bottom border
-	vector.StrokeLine(screen, 0, float32(screen.Bounds().Dy()), float32(screen.Bounds().Dx()), float32(screen.Bounds().Dy()), 1, color.Black, false)
-}
-
-// drawBoard renders the board grid, walls, and center box.
-func (g *GameState) drawBoard(screen *ebiten.Image) {
-	lineColor := color.Gray{200}
-	// lines
-	for y := range g.Board.H - 1 {
-		vector.StrokeLine(screen, 0, float32(y+1)*CELL_SIZE, float32(g.Board.W)*CELL_SIZE, float32(y+1)*CELL_SIZE, 1, lineColor, false)
-	}
-	for x := range g.Board.W - 1 {
-		vector.StrokeLine(screen, float32(x+1)*CELL_SIZE, 0, float32(x+1)*CELL_SIZE, float32(g.Board.H)*CELL_SIZE, 1, lineColor, false)
-	}
-	// walls
-	for y, rows := range g.Board.VWalls {
-		for _, x := range rows {
-			vector.StrokeLine(screen, float32(x)*CELL_SIZE, float32(y)*CELL_SIZE, float32(x)*CELL_SIZE, float32(y+1)*CELL_SIZE, 1, color.Black, false)
-		}
-	}
-	for x, cols := range g.Board.HWalls {
-		for _, y := range cols {
-			vector.StrokeLine(screen, float32(x)*CELL_SIZE, float32(y)*CELL_SIZE, float32(x+1)*CELL_SIZE, float32(y)*CELL_SIZE, 1, color.Black, false)
-		}
-	}
-	// center box
-	c := g.Board.Center()
-	vector.DrawFilledRect(screen, float32(c.TopLeft.X)*CELL_SIZE, float32(c.TopLeft.Y)*CELL_SIZE, float32(c.Size().W)*CELL_SIZE-1, float32(c.Size().H)*CELL_SIZE-1, lineColor, false)
+	g.renderer.DrawGoal(screen, g.Board.Goal)
+	g.drawPreview(screen)
+	g.renderer.DrawBorder(screen)
 }
 
 // drawActors renders all actors on the board.
@@ -152,105 +559,313 @@ func (g *GameState) drawActors(screen *ebiten.Image) {
 	}
 }
 
-// drawActor renders a single actor as a colored circle.
+// drawActor renders a single actor at its position in the animation if sliding.
 func (g *GameState) drawActor(screen *ebiten.Image, actor *hyper.Actor) {
-	p := NewPosition(actor.Point, CELL_SIZE)
-	halfCellSize := CELL_SIZE / 2
-	p = p.Add(Position{halfCellSize, halfCellSize})
-	r := halfCellSize - 2
-	vector.DrawFilledCircle(screen, p.X, p.Y, r, Color(actor.Color), true)
-	vector.StrokeCircle(screen, p.X, p.Y, r, 1, color.Black, true)
+	cellSize := g.layout.CellSize
+	p, ok := g.animator.Position(actor.Color, cellSize)
+	if !ok {
+		p = NewPosition(actor.Point, cellSize)
+	}
+	g.renderer.DrawActor(screen, actor.Color, p, g.selecting && actor.Color == g.selected)
 }
 
 // drawHistory renders all recorded moves as lines.
 func (g *GameState) drawHistory(screen *ebiten.Image) {
 	for _, record := range g.History() {
-		g.drawRecord(screen, record)
+		g.renderer.DrawRecord(screen, record)
+	}
+}
+
+// reachAlphas are the opacities of the cells reachable in 1, 2 and 3 moves.
+var reachAlphas = []uint8{0, 112, 64, 32}
+
+// reachTarget returns the actor whose reachable cells are highlighted: the hovered one, or the selected one.
+func (g *GameState) reachTarget() (hyper.Color, bool) {
+	x, y := ebiten.CursorPosition()
+	if actor, ok := g.Board.ActorAt(g.layout.ToPoint(Position{float32(x), float32(y)})); ok {
+		return actor.Color, true
 	}
+	return g.selected, g.selecting
 }
 
-// drawRecord renders a single move record as a line in the actor's color.
-func (g *GameState) drawRecord(screen *ebiten.Image, record *hyper.Record) {
-	lineColor := Color(record.Color)
-	start := adjust(Offset(record.Color), record.Start)
-	end := adjust(Offset(record.Color), record.End)
-	vector.StrokeLine(screen, start.X, start.Y, end.X, end.Y, 1, lineColor, false)
+// drawReach highlights the cells which the target actor can reach, fading out with the number of moves.
+func (g *GameState) drawReach(screen *ebiten.Image) {
+	if g.Settings.Reach == 0 || g.animator.Animating() {
+		return
+	}
+	c, ok := g.reachTarget()
+	if !ok {
+		return
+	}
+	cellSize := g.layout.CellSize
+	clr := g.theme().Palette().Color(c)
+	for p, moves := range g.Board.Reachable(c, g.Settings.Reach) {
+		if moves == 0 {
+			continue
+		}
+		pos := NewPosition(p, cellSize)
+		vector.DrawFilledRect(screen, pos.X, pos.Y, cellSize, cellSize, Translucent(clr, reachAlphas[moves]), false)
+		if g.Settings.Accessible {
+			center := pos.Add(Position{cellSize / 2, cellSize / 2})
+			g.renderer.DrawLetter(screen, strconv.Itoa(moves), center, cellSize/3, g.theme().Wall)
+		}
+	}
+}
+
+// Colors of cells in the analysis overlay, from the nearest to the goal to the farthest.
+var (
+	heatNear = color.NRGBA{0, 200, 80, 112}
+	heatFar  = color.NRGBA{230, 40, 40, 112}
+)
+
+// analysisTarget returns the actor to analyze: the one of the goal's color, or the target of reach for the black goal.
+func (g *GameState) analysisTarget() (hyper.Color, bool) {
+	if g.Board.Goal.Color != hyper.Black {
+		return g.Board.Goal.Color, true
+	}
+	return g.reachTarget()
 }
 
-func adjust(n float32, p hyper.Point) Position {
-	diff := n + CELL_SIZE/2
-	pos := NewPosition(p, CELL_SIZE)
-	return pos.Add(Position{diff, diff})
+// drawAnalysis shows the minimum number of moves for the target actor to reach the goal from each cell as a heatmap.
+// Cells from which the goal cannot be reached are crossed out.
+func (g *GameState) drawAnalysis(screen *ebiten.Image) {
+	if !g.Settings.Analysis {
+		return
+	}
+	c, ok := g.analysisTarget()
+	if !ok {
+		return
+	}
+	distances := g.Board.DistancesToGoal(c)
+	farthest := 1
+	for _, moves := range distances {
+		farthest = max(farthest, moves)
+	}
+
+	t := g.theme()
+	cellSize := g.layout.CellSize
+	width := g.layout.Px(1)
+	center := g.Board.Center()
+	for y := range g.Board.H {
+		for x := range g.Board.W {
+			p := hyper.Point{X: x, Y: y}
+			if actor, ok := g.Board.ActorAt(p); center.Contains(p) || (ok && actor.Color != c) {
+				continue
+			}
+			pos := NewPosition(p, cellSize)
+			moves, ok := distances[p]
+			if !ok {
+				inset := cellSize / 4
+				vector.StrokeLine(screen, pos.X+inset, pos.Y+inset, pos.X+cellSize-inset, pos.Y+cellSize-inset, width, t.Grid, true)
+				vector.StrokeLine(screen, pos.X+cellSize-inset, pos.Y+inset, pos.X+inset, pos.Y+cellSize-inset, width, t.Grid, true)
+				continue
+			}
+			clr := Mix(heatNear, heatFar, float64(moves)/float64(farthest))
+			vector.DrawFilledRect(screen, pos.X, pos.Y, cellSize, cellSize, clr, false)
+			g.renderer.DrawLetter(screen, strconv.Itoa(moves), pos.Add(Position{cellSize / 2, cellSize / 2}), cellSize/3, t.Wall)
+		}
+	}
 }
 
-// drawGoal renders the goal as a colored rectangle.
-func (g *GameState) drawGoal(screen *ebiten.Image) {
-	goal := g.Board.Goal
-	vector.DrawFilledRect(screen, float32(goal.X)*CELL_SIZE, float32(goal.Y)*CELL_SIZE, CELL_SIZE-1, CELL_SIZE-1, Color(goal.Color), false)
+// drawPreview renders the path and the stop position of the actor being swiped as a ghost.
+func (g *GameState) drawPreview(screen *ebiten.Image) {
+	start, dir, ok := g.SwipeEventDispatcher.Dragging()
+	if !ok {
+		return
+	}
+	actor, ok := g.Board.ActorAt(start)
+	if !ok {
+		return
+	}
+	stop := g.Board.NextStop(actor.Point, dir)
+	if actor.Point.Equals(stop) {
+		return
+	}
+
+	t := g.theme()
+	c := t.Palette().Color(actor.Color)
+	cellSize := g.layout.CellSize
+	halfCellSize := cellSize / 2
+	from := NewPosition(actor.Point, cellSize)
+	from = from.Add(Position{halfCellSize, halfCellSize})
+	to := NewPosition(stop, cellSize)
+	to = to.Add(Position{halfCellSize, halfCellSize})
+	r := halfCellSize - g.layout.Px(2)
+	vector.StrokeLine(screen, from.X, from.Y, to.X, to.Y, g.layout.Px(3), Translucent(c, 96), true)
+	vector.DrawFilledCircle(screen, to.X, to.Y, r, Translucent(c, 96), true)
+	vector.StrokeCircle(screen, to.X, to.Y, r, g.layout.Px(1), Translucent(t.Outline, 96), true)
 }
 
 // drawUI renders the UI controls panel.
+// The UI covers the whole screen so that its widgets receive the cursor position as is.
 func (g *GameState) drawUI(screen *ebiten.Image) {
-	g.clear(g.controls)
-	g.UI.Draw(g.controls)
+	g.UI.Draw(screen)
 }
 
-// createUI creates and returns the UI container with control buttons.
-func createUI(r *ResourceLoader, b *hyper.Board) (*ebitenui.UI, error) {
+// refreshHistory lists the moves of the active line and selects the current one.
+func (g *GameState) refreshHistory() {
+	if g.history == nil {
+		return
+	}
+	g.history.SetEntries(historyEntries(g.Board))
+	current := g.Board.CurrentMove()
+	g.refreshed.PushBack(current)
+	g.history.SetSelectedEntry(current)
+}
+
+// selectMove jumps to the move selected in the history list by the player.
+// The list fires selection events also for selections by refreshHistory, later on the next update of the UI,
+// so those events are skipped in order not to jump back to a move which is no longer current.
+func (g *GameState) selectMove(n *hyper.Node) {
+	if front := g.refreshed.Front(); front != nil && front.Value == n {
+		g.refreshed.Remove(front)
+		return
+	}
+	if n == g.Board.CurrentMove() {
+		return
+	}
+	g.jumpTo(n)
+}
+
+// createUI creates and returns the UI container with control buttons placed in the controls area of the layout
+// and the list of moves placed in the history area.
+func (g *GameState) createUI(l *Layout) (*ebitenui.UI, error) {
+	// the root covers the whole screen, and children are placed in areas by padding
 	root := widget.NewContainer(
-		widget.ContainerOpts.Layout(widget.NewAnchorLayout(
-			widget.AnchorLayoutOpts.Padding(widget.NewInsetsSimple(0)),
-		)),
+		widget.ContainerOpts.Layout(widget.NewAnchorLayout()),
 	)
+	area := func(r image.Rectangle) widget.Insets {
+		return widget.Insets{
+			Top:    r.Min.Y,
+			Left:   r.Min.X,
+			Right:  l.Width - r.Max.X,
+			Bottom: l.Height - r.Max.Y,
+		}
+	}
+
+	history, err := createHistoryList(g.ResourceLoader, g.theme(), l.Scale, g.selectMove)
+	if err != nil {
+		return nil, err
+	}
+	historyContainer := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewAnchorLayout()),
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
+			StretchHorizontal: true,
+			StretchVertical:   true,
+			Padding:           area(l.History),
+		})),
+	)
+	historyContainer.AddChild(history)
+	root.AddChild(historyContainer)
+	g.history = history
+	g.refreshHistory()
 
 	btnContainer := widget.NewContainer(
-		widget.ContainerOpts.Layout(widget.NewRowLayout(
-			widget.RowLayoutOpts.Direction(widget.DirectionHorizontal),
-			widget.RowLayoutOpts.Padding(widget.NewInsetsSimple(8)),
-			widget.RowLayoutOpts.Spacing(8),
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(l.Columns),
+			widget.GridLayoutOpts.Padding(widget.NewInsetsSimple(int(l.Px(CONTROLS_PADDING)))),
+			widget.GridLayoutOpts.Spacing(int(l.Px(CONTROLS_PADDING)), int(l.Px(CONTROLS_PADDING))),
 		)),
 		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
 			HorizontalPosition: widget.AnchorLayoutPositionCenter,
 			VerticalPosition:   widget.AnchorLayoutPositionCenter,
+			Padding:            area(l.Controls),
 		})),
 	)
 	root.AddChild(btnContainer)
 
-	undoBtn, err := createButton(r, "Undo", func(args *widget.ButtonClickedEventArgs) {
-		b.Undo()
-	})
-	if err != nil {
-		return nil, err
+	for _, b := range g.controls() {
+		onclick := b.onclick
+		clicked := func(args *widget.ButtonClickedEventArgs) {
+			g.emit(&GameEvent{Kind: ClickEvent})
+			onclick(args)
+		}
+		btn, err := createButton(g.ResourceLoader, &g.theme().Button, l.Scale, b.label, clicked)
+		if err != nil {
+			return nil, err
+		}
+		btnContainer.AddChild(btn)
 	}
-	btnContainer.AddChild(undoBtn)
 
-	redoBtn, err := createButton(r, "Redo", func(args *widget.ButtonClickedEventArgs) {
-		b.Redo()
-	})
-	if err != nil {
-		return nil, err
-	}
-	btnContainer.AddChild(redoBtn)
+	return &ebitenui.UI{
+		Container: root,
+	}, nil
+}
 
-	resetBtn, err := createButton(r, "Reset", func(args *widget.ButtonClickedEventArgs) {
-		b.Reset()
-	})
-	if err != nil {
-		return nil, err
+// controls returns the buttons of the controls panel.
+// Buttons push ControlEvents so that they are handled in the same way as other inputs.
+func (g *GameState) controls() []control {
+	s := g.Settings
+	action := func(a Action) widget.ButtonClickedHandlerFunc {
+		return func(args *widget.ButtonClickedEventArgs) {
+			g.ControlEventDispatcher.Push(&ControlEvent{Kind: ActionEvent, Action: a})
+		}
 	}
-	btnContainer.AddChild(resetBtn)
+	return []control{
+		{"Undo", action(UndoAction)},
+		{"Redo", action(RedoAction)},
+		{"Reset", action(ResetAction)},
+		{"New Game", action(NewGameAction)},
+		{"Edit", action(EditAction)},
+		{"Share", action(ShareAction)},
+		{"Stats", action(StatsAction)},
+		{"Daily", action(DailyAction)},
+		{"Packs", action(PacksAction)},
+		{"Tutorial", action(TutorialAction)},
+		{speedLabel(s.AnimationSpeed), func(args *widget.ButtonClickedEventArgs) {
+			s.AnimationSpeed = s.AnimationSpeed.Next()
+			args.Button.Text().Label = speedLabel(s.AnimationSpeed)
+		}},
+		{toggleLabel("Shapes", s.Accessible), func(args *widget.ButtonClickedEventArgs) {
+			s.Accessible = !s.Accessible
+			args.Button.Text().Label = toggleLabel("Shapes", s.Accessible)
+		}},
+		{toggleLabel("Contrast", s.HighContrast), func(args *widget.ButtonClickedEventArgs) {
+			s.HighContrast = !s.HighContrast
+			// rebuild the renderer and the UI with the colors
+			g.resized = g.layout
+		}},
+		{"Theme: " + g.theme().Name, func(args *widget.ButtonClickedEventArgs) {
+			g.nextTheme()
+		}},
+		{volumeLabel(s.Volume), func(args *widget.ButtonClickedEventArgs) {
+			s.Volume = s.NextVolume()
+			args.Button.Text().Label = volumeLabel(s.Volume)
+		}},
+		{toggleLabel("Sound", !s.Muted), action(MuteAction)},
+		{reachLabel(s.Reach), func(args *widget.ButtonClickedEventArgs) {
+			s.Reach = s.NextReach()
+			args.Button.Text().Label = reachLabel(s.Reach)
+		}},
+		{toggleLabel("Analysis", s.Analysis), func(args *widget.ButtonClickedEventArgs) {
+			s.Analysis = !s.Analysis
+			args.Button.Text().Label = toggleLabel("Analysis", s.Analysis)
+		}},
+	}
+}
 
-	newGameBtn, err := createButton(r, "New Game", func(args *widget.ButtonClickedEventArgs) {
-		if err := b.NewGame(); err != nil {
-			log.Fatal(err)
-		}
-	})
-	if err != nil {
-		return nil, err
+// speedLabel returns the label of the button to change the animation speed.
+func speedLabel(s AnimationSpeed) string {
+	return "Anim: " + s.String()
+}
+
+// reachLabel returns the label of the button to change the number of moves to highlight reachable cells.
+func reachLabel(moves int) string {
+	if moves == 0 {
+		return "Reach: Off"
 	}
-	btnContainer.AddChild(newGameBtn)
+	return fmt.Sprintf("Reach: %d", moves)
+}
 
-	return &ebitenui.UI{
-		Container: root,
-	}, nil
+// volumeLabel returns the label of the button to change the volume.
+func volumeLabel(v float64) string {
+	return fmt.Sprintf("Volume: %d%%", int(v*100))
+}
+
+// toggleLabel returns the label of the button to toggle a setting.
+func toggleLabel(name string, on bool) string {
+	if on {
+		return name + ": On"
+	}
+	return name + ": Off"
 }
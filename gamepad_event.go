@@ -0,0 +1,93 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// GAMEPAD_STICK_THRESHOLD is the axis value over which the stick is regarded as tilted.
+const GAMEPAD_STICK_THRESHOLD = 0.5
+
+// gamepadButtons maps buttons of the standard gamepad layout to ControlEvents.
+var gamepadButtons = map[ebiten.StandardGamepadButton]ControlEvent{
+	ebiten.StandardGamepadButtonFrontTopLeft:  {Kind: SelectPrevEvent},
+	ebiten.StandardGamepadButtonFrontTopRight: {Kind: SelectNextEvent},
+	ebiten.StandardGamepadButtonLeftTop:       {Kind: MoveEvent, Direction: hyper.North},
+	ebiten.StandardGamepadButtonLeftLeft:      {Kind: MoveEvent, Direction: hyper.West},
+	ebiten.StandardGamepadButtonLeftRight:     {Kind: MoveEvent, Direction: hyper.East},
+	ebiten.StandardGamepadButtonLeftBottom:    {Kind: MoveEvent, Direction: hyper.South},
+	ebiten.StandardGamepadButtonRightBottom:   {Kind: ActionEvent, Action: UndoAction},
+	ebiten.StandardGamepadButtonRightRight:    {Kind: ActionEvent, Action: RedoAction},
+	ebiten.StandardGamepadButtonRightLeft:     {Kind: ActionEvent, Action: ResetAction},
+	ebiten.StandardGamepadButtonRightTop:      {Kind: ActionEvent, Action: NewGameAction},
+}
+
+// GamepadEventHandler handles input events from gamepads with the standard layout.
+// Gamepads can be connected and disconnected at any time.
+type GamepadEventHandler struct {
+	ids     []ebiten.GamepadID
+	buttons []ebiten.StandardGamepadButton
+	sticks  map[ebiten.GamepadID]hyper.Direction // last direction of the left stick
+}
+
+// HandleControl returns ControlEvents for buttons which are just pressed and sticks which are just tilted.
+func (h *GamepadEventHandler) HandleControl() []*ControlEvent {
+	h.updateGamepads()
+
+	events := []*ControlEvent{}
+	for _, id := range h.ids {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		h.buttons = inpututil.AppendJustPressedStandardGamepadButtons(id, h.buttons[:0])
+		for _, button := range h.buttons {
+			if ev, ok := gamepadButtons[button]; ok {
+				events = append(events, &ev)
+			}
+		}
+		if d, ok := h.handleStick(id); ok {
+			events = append(events, &ControlEvent{Kind: MoveEvent, Direction: d})
+		}
+	}
+	return events
+}
+
+// updateGamepads tracks connected and disconnected gamepads.
+func (h *GamepadEventHandler) updateGamepads() {
+	if h.sticks == nil {
+		h.sticks = map[ebiten.GamepadID]hyper.Direction{}
+	}
+	h.ids = inpututil.AppendJustConnectedGamepadIDs(h.ids)
+	h.ids = slices.DeleteFunc(h.ids, func(id ebiten.GamepadID) bool {
+		if inpututil.IsGamepadJustDisconnected(id) {
+			delete(h.sticks, id)
+			return true
+		}
+		return false
+	})
+}
+
+// handleStick returns the direction of the left stick only when it is just tilted to the direction.
+func (h *GamepadEventHandler) handleStick(id ebiten.GamepadID) (hyper.Direction, bool) {
+	x := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	y := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+
+	var d hyper.Direction
+	switch {
+	case max(-x, x) >= max(-y, y) && x <= -GAMEPAD_STICK_THRESHOLD:
+		d = hyper.West
+	case max(-x, x) >= max(-y, y) && x >= GAMEPAD_STICK_THRESHOLD:
+		d = hyper.East
+	case y <= -GAMEPAD_STICK_THRESHOLD:
+		d = hyper.North
+	case y >= GAMEPAD_STICK_THRESHOLD:
+		d = hyper.South
+	}
+
+	last := h.sticks[id]
+	h.sticks[id] = d
+	return d, d != 0 && d != last
+}
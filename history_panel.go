@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ebitenui/ebitenui/image"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+// historyEntries returns the nodes of the active line of the history tree, starting with the root.
+func historyEntries(b *hyper.Board) []any {
+	entries := []any{}
+	for n := b.HistoryTree(); n != nil; n = n.Active() {
+		entries = append(entries, n)
+	}
+	return entries
+}
+
+// historyLabel returns the label of an entry of the history list, with the number of the move, the color and the direction.
+func historyLabel(e any) string {
+	n, ok := e.(*hyper.Node)
+	if !ok {
+		return ""
+	}
+	if n.Record == nil {
+		return "0. Start"
+	}
+	return fmt.Sprintf("%d. %s %s", n.Steps, n.Color, n.Direction)
+}
+
+// createHistoryList creates a list of moves which calls onselect with the node of the selected move.
+func createHistoryList(r *ResourceLoader, t *Theme, scale float64, onselect func(n *hyper.Node)) (*widget.List, error) {
+	font, err := r.FontFace(int(12 * scale))
+	if err != nil {
+		return nil, err
+	}
+	handle, err := loadButtonImage(r, &t.Button)
+	if err != nil {
+		return nil, err
+	}
+	background := image.NewNineSliceColor(t.Background)
+	track := image.NewNineSliceColor(t.Grid)
+
+	return widget.NewList(
+		widget.ListOpts.ContainerOpts(widget.ContainerOpts.WidgetOpts(
+			widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
+				StretchHorizontal: true,
+				StretchVertical:   true,
+			}),
+		)),
+		widget.ListOpts.ScrollContainerOpts(widget.ScrollContainerOpts.Image(&widget.ScrollContainerImage{
+			Idle:     background,
+			Disabled: background,
+			Mask:     background,
+		})),
+		widget.ListOpts.SliderOpts(
+			widget.SliderOpts.Images(&widget.SliderTrackImage{
+				Idle:  track,
+				Hover: track,
+			}, handle),
+			widget.SliderOpts.MinHandleSize(int(5*scale)),
+			widget.SliderOpts.TrackPadding(widget.NewInsetsSimple(int(2*scale))),
+		),
+		widget.ListOpts.HideHorizontalSlider(),
+		// arrow keys move actors
+		widget.ListOpts.DisableDefaultKeys(true),
+		widget.ListOpts.EntryFontFace(font),
+		widget.ListOpts.EntryColor(&widget.ListEntryColor{
+			Selected:                   t.Button.Text,
+			Unselected:                 t.Wall,
+			SelectedBackground:         t.Button.Pressed,
+			SelectingBackground:        t.Button.Hover,
+			SelectingFocusedBackground: t.Button.Hover,
+			SelectedFocusedBackground:  t.Button.Pressed,
+			FocusedBackground:          t.Button.Hover,
+			DisabledUnselected:         t.Grid,
+			DisabledSelected:           t.Grid,
+			DisabledSelectedBackground: t.Button.Idle,
+		}),
+		widget.ListOpts.EntryLabelFunc(historyLabel),
+		widget.ListOpts.EntryTextPadding(widget.NewInsetsSimple(int(3*scale))),
+		widget.ListOpts.EntryTextPosition(widget.TextPositionStart, widget.TextPositionCenter),
+		widget.ListOpts.EntrySelectedHandler(func(args *widget.ListEntrySelectedEventArgs) {
+			if n, ok := args.Entry.(*hyper.Node); ok {
+				onselect(n)
+			}
+		}),
+	), nil
+}
@@ -16,8 +16,9 @@ import (
 
 // Board represents the game board with actors, walls, and goals.
 type Board struct {
-	rand    *rand.Rand
-	history *History
+	rand          *rand.Rand
+	history       *History
+	subscriptions []*subscription
 	Placement
 	Goal
 	*Mapdata
@@ -28,13 +29,19 @@ type Board struct {
 
 // NewBoard creates and initializes a new game board with the given size and placement algorithms.
 func NewBoard(size Size, p Placement) (*Board, error) {
-	b := &Board{
-		rand:      rand.New(rand.NewSource(time.Now().Unix())),
-		history:   &History{},
-		Actors:    map[Color]*Actor{},
-		Mapdata:   NewMapdata(size),
-		Placement: p,
-	}
+	return NewBoardOnMap(NewMapdata(size), p)
+}
+
+// NewBoardOnMap creates and initializes a new game board with the walls and placement algorithms.
+func NewBoardOnMap(m *Mapdata, p Placement) (*Board, error) {
+	return NewSeededBoardOnMap(m, p, time.Now().UnixNano())
+}
+
+// NewSeededBoardOnMap creates and initializes a new game board like NewBoardOnMap,
+// placing actors and goals at random by the seed so that the same seed gives the same board.
+func NewSeededBoardOnMap(m *Mapdata, p Placement, seed int64) (*Board, error) {
+	b := newBoard(m, p)
+	b.rand = rand.New(rand.NewSource(seed))
 
 	// place actors
 	for _, color := range AllColors {
@@ -49,6 +56,17 @@ func NewBoard(size Size, p Placement) (*Board, error) {
 	return b, nil
 }
 
+// newBoard creates an empty board with the walls and placement algorithms.
+func newBoard(m *Mapdata, p Placement) *Board {
+	return &Board{
+		rand:      rand.New(rand.NewSource(time.Now().Unix())),
+		history:   &History{},
+		Actors:    map[Color]*Actor{},
+		Mapdata:   m,
+		Placement: p,
+	}
+}
+
 // NewGame resets the board for a new game, clearing history and placing a new goal.
 func (b *Board) NewGame() error {
 	b.Goaled = false
@@ -56,6 +74,7 @@ func (b *Board) NewGame() error {
 	if err := b.PlaceGoal(); err != nil {
 		return err
 	}
+	b.emit(NewGameEvent, nil)
 	return nil
 }
 
@@ -96,11 +115,34 @@ func (b *Board) PlaceGoal() error {
 	return fmt.Errorf("unable to place goal")
 }
 
-// History returns all recorded moves on this board.
+// History returns the recorded moves of the active line on this board.
 func (b *Board) History() []*Record {
 	return b.history.Records()
 }
 
+// HistoryTree returns the root of the history tree, which represents the initial state.
+func (b *Board) HistoryTree() *Node {
+	return b.history.Root()
+}
+
+// CurrentMove returns the node of the last move in the history tree.
+func (b *Board) CurrentMove() *Node {
+	return b.history.Current()
+}
+
+// InitialActors returns the positions of actors before the moves in history.
+func (b *Board) InitialActors() map[Color]Point {
+	actors := map[Color]Point{}
+	for c, actor := range b.Actors {
+		actors[c] = actor.Point
+	}
+	path := b.history.Current().Path()
+	for i := len(path) - 1; i >= 0; i-- {
+		actors[path[i].Color] = path[i].Start
+	}
+	return actors
+}
+
 // Steps returns the number of moves taken so far.
 func (b *Board) Steps() int {
 	return b.history.Len()
@@ -131,71 +173,133 @@ func (b *Board) MoveActor(actor *Actor, d Direction) (pos Point, ok bool) {
 		return
 	}
 	ok = true
-	b.history.Push(&Record{
+	r := &Record{
 		Color:     actor.Color,
 		Direction: d,
 		Start:     actor.Point,
 		End:       pos,
-	})
+	}
+	b.history.Push(r)
 	actor.MoveTo(pos)
+	b.emit(MovedEvent, r)
+	b.checkGoal(r)
 
-	if b.Goal.Reached(*actor) {
+	return
+}
+
+// checkGoal marks the board as goaled if the actor moved by the record reached the goal.
+func (b *Board) checkGoal(r *Record) {
+	if !b.Goaled && b.Goal.Reached(*b.Actors[r.Color]) {
 		b.Goaled = true
+		b.emit(GoalReachedEvent, r)
 	}
-
-	return
 }
 
 // Reset undoes all moves, returning the board to its initial state.
 func (b *Board) Reset() {
 	for b.history.Len() > 0 {
-		b.Undo()
+		b.undo()
 	}
+	b.emit(ResetEvent, nil)
 }
 
-// Undo reverts the last move.
-func (b *Board) Undo() {
+// Undo reverts the last move and returns it, or nil if there is nothing to undo.
+func (b *Board) Undo() *Record {
+	r := b.undo()
+	if r != nil {
+		b.emit(UndoneEvent, r)
+	}
+	return r
+}
+
+// undo reverts the last move without notifying subscribers.
+func (b *Board) undo() *Record {
 	r := b.history.Undo()
 	if r == nil {
-		return
+		return nil
 	}
 	b.Actors[r.Color].Point = r.Start
 	b.Goaled = false
+	return r
 }
 
-// Redo replays the next move in history.
-func (b *Board) Redo() {
+// Redo replays the next move in history and returns it, or nil if there is nothing to redo.
+func (b *Board) Redo() *Record {
 	r := b.history.Redo()
 	if r == nil {
-		return
+		return nil
 	}
 	b.Actors[r.Color].Point = r.End
-	if b.Goal.Reached(*b.Actors[r.Color]) {
-		b.Goaled = true
+	b.emit(RedoneEvent, r)
+	b.checkGoal(r)
+	return r
+}
+
+// JumpTo brings the board to the state after the move of the node, switching branches if needed.
+// It returns false if the node is current or does not belong to the history of this board.
+func (b *Board) JumpTo(n *Node) bool {
+	undone, redone := b.history.JumpTo(n)
+	if len(undone) == 0 && len(redone) == 0 {
+		return false
 	}
+	for _, r := range undone {
+		b.Actors[r.Color].Point = r.Start
+	}
+	for _, r := range redone {
+		b.Actors[r.Color].Point = r.End
+	}
+	b.updateGoaled(n)
+	b.emit(JumpedEvent, n.Record)
+	return true
+}
+
+// updateGoaled marks the board as goaled if the goal is reached by any move on the way to the node.
+func (b *Board) updateGoaled(n *Node) {
+	b.Goaled = false
+	for _, node := range n.Path() {
+		if b.Goal.Reached(Actor{node.Color, node.End}) {
+			b.Goaled = true
+		}
+	}
+}
+
+// SwitchBranch jumps to the alternative of the last move which is delta away, wrapping around.
+// It returns false if there is no alternative.
+func (b *Board) SwitchBranch(delta int) bool {
+	siblings, i := b.history.Siblings()
+	n := len(siblings)
+	if n < 2 {
+		return false
+	}
+	return b.JumpTo(siblings[((i+delta)%n+n)%n])
 }
 
 // NextStop calculates where an actor moving in a direction would stop.
 func (b *Board) NextStop(current Point, d Direction) Point {
+	return b.nextStop(current, d, slices.Collect(maps.Values(b.Actors)))
+}
+
+// nextStop calculates where an actor moving in a direction would stop, blocked by the given actors.
+func (b *Board) nextStop(current Point, d Direction, actors []*Actor) Point {
 	switch d {
 	case North:
-		return b.nextStopNorth(current)
+		return b.nextStopNorth(current, actors)
 	case West:
-		return b.nextStopWest(current)
+		return b.nextStopWest(current, actors)
 	case South:
-		return b.nextStopSouth(current)
+		return b.nextStopSouth(current, actors)
 	case East:
-		return b.nextStopEast(current)
+		return b.nextStopEast(current, actors)
 	}
 	return Point{}
 }
 
-func (b *Board) nextStopNorth(current Point) Point {
+func (b *Board) nextStopNorth(current Point, actors []*Actor) Point {
 	// find y-index of actor who is:
 	//   1. on the current column
 	//   2. nearer to the north than current
-	actors := slicetools.FilterMap(
-		slices.Collect(maps.Values(b.Actors)),
+	blockers := slicetools.FilterMap(
+		actors,
 		func(actor *Actor) bool {
 			return actor.X == current.X && actor.Y < current.Y
 		},
@@ -213,19 +317,19 @@ func (b *Board) nextStopNorth(current Point) Point {
 
 	// find x which is nearest to the current position
 	ys := []int{0}
-	ys = append(ys, actors...)
+	ys = append(ys, blockers...)
 	ys = append(ys, walls...)
 	y := slices.Max(ys)
 
 	return Point{current.X, y}
 }
 
-func (b *Board) nextStopSouth(current Point) Point {
+func (b *Board) nextStopSouth(current Point, actors []*Actor) Point {
 	// find y-index of actor who is:
 	//   1. on the current column
 	//   2. nearer to the south than current
-	actors := slicetools.FilterMap(
-		slices.Collect(maps.Values(b.Actors)),
+	blockers := slicetools.FilterMap(
+		actors,
 		func(actor *Actor) bool {
 			return actor.X == current.X && actor.Y > current.Y
 		},
@@ -243,19 +347,19 @@ func (b *Board) nextStopSouth(current Point) Point {
 
 	// find x which is nearest to the current position
 	ys := []int{b.Mapdata.H}
-	ys = append(ys, actors...)
+	ys = append(ys, blockers...)
 	ys = append(ys, walls...)
 	y := slices.Min(ys) - 1
 
 	return Point{current.X, y}
 }
 
-func (b *Board) nextStopWest(current Point) Point {
+func (b *Board) nextStopWest(current Point, actors []*Actor) Point {
 	// find x-indices of actors who are:
 	//   1. on the current row
 	//   2. nearer to the west than current
-	actors := slicetools.FilterMap(
-		slices.Collect(maps.Values(b.Actors)),
+	blockers := slicetools.FilterMap(
+		actors,
 		func(actor *Actor) bool {
 			return actor.Y == current.Y && actor.X < current.X
 		},
@@ -273,19 +377,19 @@ func (b *Board) nextStopWest(current Point) Point {
 
 	// find x which is nearest to the current position
 	xs := []int{0}
-	xs = append(xs, actors...)
+	xs = append(xs, blockers...)
 	xs = append(xs, walls...)
 	x := slices.Max(xs)
 
 	return Point{x, current.Y}
 }
 
-func (b *Board) nextStopEast(current Point) Point {
+func (b *Board) nextStopEast(current Point, actors []*Actor) Point {
 	// find x-index of actor who is:
 	//   1. on the current row
 	//   2. nearer to the east than current
-	actors := slicetools.FilterMap(
-		slices.Collect(maps.Values(b.Actors)),
+	blockers := slicetools.FilterMap(
+		actors,
 		func(actor *Actor) bool {
 			return actor.Y == current.Y && actor.X > current.X
 		},
@@ -303,7 +407,7 @@ func (b *Board) nextStopEast(current Point) Point {
 
 	// find x which is nearest to the current position
 	xs := []int{b.Mapdata.W}
-	xs = append(xs, actors...)
+	xs = append(xs, blockers...)
 	xs = append(xs, walls...)
 	x := slices.Min(xs) - 1
 
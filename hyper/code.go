@@ -0,0 +1,129 @@
+package hyper
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// CODE_VERSION is the version of the format of puzzle codes, written in the first byte.
+const CODE_VERSION = 1
+
+// MAX_CODE_SIZE is the maximum size of the decompressed data of puzzle codes in bytes.
+// It is enough for the board of 255x255 cells.
+const MAX_CODE_SIZE = 3 + (255*255*2+7)/8 + 2*5 + 3
+
+// ErrInvalidCode is returned when a puzzle code cannot be decoded.
+var ErrInvalidCode = errors.New("invalid puzzle code")
+
+// Code returns a short URL-safe code of the puzzle, which is decoded by ParseCode.
+//
+// The code is the base64url encoding of the data compressed by DEFLATE:
+// the version, the width and the height of the board, the wall bits of each cell packed in 2 bits,
+// the positions of actors in the order of AllColors, and the color and the position of the goal.
+func (p *Puzzle) Code() (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	size := p.Size()
+	if size.W > 255 || size.H > 255 {
+		return "", fmt.Errorf("board is too large: %dx%d", size.W, size.H)
+	}
+
+	data := []byte{CODE_VERSION, byte(size.W), byte(size.H)}
+	walls := make([]byte, (size.W*size.H*2+7)/8)
+	for y, row := range p.Walls {
+		for x, n := range row {
+			i := (y*size.W + x) * 2
+			walls[i/8] |= byte(n&int(North|West)) << (i % 8)
+		}
+	}
+	data = append(data, walls...)
+	for _, c := range AllColors {
+		pos := p.Actors[c]
+		data = append(data, byte(pos.X), byte(pos.Y))
+	}
+	data = append(data, byte(p.Goal.Color), byte(p.Goal.X), byte(p.Goal.Y))
+
+	buf := &bytes.Buffer{}
+	w, err := flate.NewWriter(buf, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(data); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// ParseCode decodes the puzzle from the code returned by Puzzle.Code.
+func ParseCode(code string) (*Puzzle, error) {
+	compressed, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCode, err)
+	}
+	r := flate.NewReader(bytes.NewReader(compressed))
+	defer r.Close()
+	data, err := io.ReadAll(io.LimitReader(r, MAX_CODE_SIZE+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCode, err)
+	}
+	if len(data) > MAX_CODE_SIZE {
+		return nil, fmt.Errorf("%w: too large", ErrInvalidCode)
+	}
+	if len(data) < 3 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidCode)
+	}
+	if data[0] != CODE_VERSION {
+		return nil, fmt.Errorf("%w: unknown version %d", ErrInvalidCode, data[0])
+	}
+
+	size := Size{W: int(data[1]), H: int(data[2])}
+	wallsLen := (size.W*size.H*2 + 7) / 8
+	if len(data) != 3+wallsLen+2*len(AllColors)+3 {
+		return nil, fmt.Errorf("%w: unexpected length %d", ErrInvalidCode, len(data))
+	}
+	walls, rest := data[3:3+wallsLen], data[3+wallsLen:]
+
+	p := &Puzzle{
+		Walls:  make([][]int, size.H),
+		Actors: map[Color]Point{},
+	}
+	for y := range p.Walls {
+		p.Walls[y] = make([]int, size.W)
+		for x := range p.Walls[y] {
+			i := (y*size.W + x) * 2
+			p.Walls[y][x] = int(walls[i/8]>>(i%8)) & int(North|West)
+		}
+	}
+	for i, c := range AllColors {
+		p.Actors[c] = Point{int(rest[2*i]), int(rest[2*i+1])}
+	}
+	goal := rest[2*len(AllColors):]
+	p.Goal = Goal{Color(goal[0]), Point{int(goal[1]), int(goal[2])}}
+	if !slices.Contains(AllColors, p.Goal.Color) {
+		return nil, fmt.Errorf("%w: unknown color %d", ErrInvalidCode, goal[0])
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCode, err)
+	}
+	return p, nil
+}
+
+// NewBoardFromCode creates a board of the puzzle of the code returned by Puzzle.Code.
+// The placement algorithms are used for new games started on the board.
+func NewBoardFromCode(code string, placement Placement) (*Board, error) {
+	p, err := ParseCode(code)
+	if err != nil {
+		return nil, err
+	}
+	return p.NewBoard(placement)
+}
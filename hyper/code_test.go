@@ -0,0 +1,132 @@
+package hyper_test
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+func TestPuzzle_Code(t *testing.T) {
+	large := hyper.NewMapdata(hyper.Size{16, 16})
+	large.PutHWall(hyper.Point{15, 15})
+
+	testcases := []struct {
+		Name     string
+		Expected *hyper.Puzzle
+	}{
+		{
+			"4x4",
+			&hyper.Puzzle{
+				Walls: [][]int{
+					{0, 0, 0, 0},
+					{0, 3, 1, 2},
+					{0, 2, 0, 3},
+					{0, 1, 3, 0},
+				},
+				Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+				Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+			},
+		},
+		{
+			"16x16",
+			&hyper.Puzzle{
+				Walls:  large.ToSlice(),
+				Actors: map[hyper.Color]hyper.Point{hyper.Red: {1, 2}, hyper.Green: {6, 1}, hyper.Blue: {2, 6}, hyper.Yellow: {12, 12}, hyper.Black: {0, 7}},
+				Goal:   hyper.Goal{Color: hyper.Black, Point: hyper.Point{15, 14}},
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			expected := testcase.Expected
+			code, err := expected.Code()
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := hyper.ParseCode(code)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.EqualFunc(expected.Walls, actual.Walls, slices.Equal) {
+				t.Errorf("walls: expected %v, actual %v", expected.Walls, actual.Walls)
+			}
+			if !maps.Equal(expected.Actors, actual.Actors) {
+				t.Errorf("actors: expected %v, actual %v", expected.Actors, actual.Actors)
+			}
+			if expected.Goal != actual.Goal {
+				t.Errorf("goal: expected %v, actual %v", expected.Goal, actual.Goal)
+			}
+		})
+	}
+}
+
+func TestParseCode(t *testing.T) {
+	p := &hyper.Puzzle{
+		Walls: [][]int{
+			{0, 0, 0, 0},
+			{0, 3, 1, 2},
+			{0, 2, 0, 3},
+			{0, 1, 3, 0},
+		},
+		Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+		Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+	}
+	valid, err := p.Code()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testcases := []struct {
+		Name string
+		Code string
+		Err  error
+	}{
+		{"valid", valid, nil},
+		{"empty", "", hyper.ErrInvalidCode},
+		{"not base64", "#!", hyper.ErrInvalidCode},
+		{"not compressed", "AAAA", hyper.ErrInvalidCode},
+		{"truncated", valid[:len(valid)/2], hyper.ErrInvalidCode},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			_, err := hyper.ParseCode(testcase.Code)
+			if !errors.Is(err, testcase.Err) {
+				t.Errorf("expected error %v, but got %v", testcase.Err, err)
+			}
+		})
+	}
+}
+
+func TestNewBoardFromCode(t *testing.T) {
+	p := &hyper.Puzzle{
+		Walls: [][]int{
+			{0, 0, 0, 0},
+			{0, 3, 1, 2},
+			{0, 2, 0, 3},
+			{0, 1, 3, 0},
+		},
+		Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+		Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+	}
+	code, err := p.Code()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := hyper.NewBoardFromCode(code, hyper.Placement{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for c, pos := range p.Actors {
+		if !b.Actors[c].Point.Equals(pos) {
+			t.Errorf("%s actor: expected %v, actual %v", c, pos, b.Actors[c].Point)
+		}
+	}
+	if b.Goal != p.Goal {
+		t.Errorf("goal: expected %v, actual %v", p.Goal, b.Goal)
+	}
+	t.Logf("code of %d chars: %s", len(code), code)
+}
@@ -1,5 +1,8 @@
 package hyper
 
+import "fmt"
+
+// Color represents the color of actors and goals.
 type Color int
 
 // Color constants for game actors and goals.
@@ -28,6 +31,31 @@ func (c Color) String() string {
 	return "unknown Color"
 }
 
+// ParseColor returns the Color whose string representation is s.
+func ParseColor(s string) (Color, error) {
+	for _, c := range AllColors {
+		if c.String() == s {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown color: %s", s)
+}
+
+// MarshalText implements encoding.TextMarshaler so that colors are written by name in JSON.
+func (c Color) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Color) UnmarshalText(text []byte) error {
+	v, err := ParseColor(string(text))
+	if err != nil {
+		return err
+	}
+	*c = v
+	return nil
+}
+
 // AllColors is a slice containing all valid Color values.
 var AllColors = []Color{
 	Red,
@@ -50,3 +78,12 @@ var ColorWeights = []int{
 func ColorAtRandom() Color {
 	return Choice(AllColors, ColorWeights)
 }
+
+// colorAtRandom returns a random Color drawn from the random source of the board, weighted by its ColorWeights if set.
+func (b *Board) colorAtRandom() Color {
+	weights := b.ColorWeights
+	if weights == nil {
+		weights = ColorWeights
+	}
+	return ChoiceBy(b.rand, AllColors, weights)
+}
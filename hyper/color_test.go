@@ -0,0 +1,33 @@
+package hyper_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+func TestColor_UnmarshalText(t *testing.T) {
+	for _, c := range hyper.AllColors {
+		t.Run(c.String(), func(t *testing.T) {
+			data, err := json.Marshal(map[hyper.Color]int{c: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual := map[hyper.Color]int{}
+			if err := json.Unmarshal(data, &actual); err != nil {
+				t.Fatal(err)
+			}
+			if actual[c] != 1 {
+				t.Errorf("unexpected value: data = %s, actual = %+v", data, actual)
+			}
+		})
+	}
+
+	t.Run("unknown color", func(t *testing.T) {
+		var c hyper.Color
+		if err := c.UnmarshalText([]byte("Purple")); err == nil {
+			t.Errorf("no error: %+v", c)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package hyper
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// DAILY_DATE_FORMAT is the layout of dates of daily puzzles.
+const DAILY_DATE_FORMAT = time.DateOnly
+
+// DAILY_MAX_ATTEMPTS is how many boards are drawn for the daily puzzle of a date until one of them is a valid puzzle.
+const DAILY_MAX_ATTEMPTS = 100
+
+// DailySeed returns the seed of the daily puzzle of the date, such as 20240131 for January 31, 2024.
+// Only the year, the month and the day of the date are used, so callers should pass the date in UTC for players all over the world to get the same puzzle.
+func DailySeed(date time.Time) int64 {
+	return int64(date.Year()*10000 + int(date.Month())*100 + date.Day())
+}
+
+// NewDailyBoard creates the board of the daily puzzle of the date.
+// The map, the positions of actors and the goal are all chosen by the seed of the date,
+// so that the same maps and placement algorithms give the same board on the same date.
+// Boards are drawn again by the seed until the puzzle is valid, as placement algorithms may put the goal out of the board or under an actor.
+func NewDailyBoard(maps []*Mapdata, date time.Time, p Placement) (*Board, error) {
+	if len(maps) == 0 {
+		return nil, errors.New("no maps for the daily puzzle")
+	}
+	src := rand.New(rand.NewSource(DailySeed(date)))
+	m := maps[src.Intn(len(maps))]
+	for range DAILY_MAX_ATTEMPTS {
+		b, err := NewSeededBoardOnMap(m.Clone(), p, src.Int63())
+		if err != nil {
+			return nil, err
+		}
+		if NewPuzzle(b).Validate() == nil {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("no valid daily puzzle in %d attempts on %s", DAILY_MAX_ATTEMPTS, date.Format(DAILY_DATE_FORMAT))
+}
+
+// DailyScore is the result of the first attempt at the daily puzzle.
+type DailyScore struct {
+	Date   string  // date of the puzzle in DAILY_DATE_FORMAT
+	Steps  int     // moves taken to reach the goal
+	Colors []Color // colors of the actors moved, in order
+}
+
+// NewDailyScore returns the score of the daily puzzle of the date solved on the board.
+func NewDailyScore(date time.Time, b *Board) *DailyScore {
+	s := &DailyScore{Date: date.Format(DAILY_DATE_FORMAT)}
+	for _, r := range b.History() {
+		s.Colors = append(s.Colors, r.Color)
+	}
+	s.Steps = len(s.Colors)
+	return s
+}
+
+// dailyMarks are the emoji representing moves of actors of each color in the share string.
+var dailyMarks = map[Color]string{
+	Red:    "🟥",
+	Green:  "🟩",
+	Blue:   "🟦",
+	Yellow: "🟨",
+	Black:  "⬛",
+}
+
+// Summary returns the number of steps taken to solve the puzzle in words.
+func (s *DailyScore) Summary() string {
+	if s.Steps == 1 {
+		return "Solved in 1 step"
+	}
+	return fmt.Sprintf("Solved in %d steps", s.Steps)
+}
+
+// Share returns the text to share the score, which shows the number of steps and the colors of moved actors without revealing the moves.
+func (s *DailyScore) Share() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Hyper Tux Daily %s\n%s\n", s.Date, s.Summary())
+	for _, c := range s.Colors {
+		sb.WriteString(dailyMarks[c])
+	}
+	return sb.String()
+}
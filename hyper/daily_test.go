@@ -0,0 +1,115 @@
+package hyper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+func TestNewDailyBoard(t *testing.T) {
+	maps := []*hyper.Mapdata{
+		hyper.NewMapdata(hyper.Size{W: 8, H: 8}),
+		hyper.NewMapdata(hyper.Size{W: 16, H: 16}),
+	}
+	placement := hyper.Placement{Actor: hyper.PlaceActorAtRandom, Goal: hyper.PlaceGoalNearByWalls}
+	code := func(t *testing.T, date time.Time) string {
+		b, err := hyper.NewDailyBoard(maps, date, placement)
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, err := hyper.NewPuzzle(b).Code()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	date := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	expected := code(t, date)
+
+	t.Run("same date", func(t *testing.T) {
+		// the time of the day does not matter
+		if actual := code(t, date.Add(23*time.Hour)); actual != expected {
+			t.Errorf("expected the same puzzle %s, but got %s", expected, actual)
+		}
+	})
+
+	t.Run("other dates", func(t *testing.T) {
+		for i := 1; i <= 7; i++ {
+			if code(t, date.AddDate(0, 0, i)) != expected {
+				return
+			}
+		}
+		t.Errorf("expected other puzzles in a week, but got the same %s", expected)
+	})
+
+	t.Run("valid puzzles all year", func(t *testing.T) {
+		// walls at the edges let the goal be placed out of the board
+		m := hyper.NewMapdata(hyper.Size{W: 16, H: 16})
+		m.PutHWall(hyper.Point{5, 0})
+		m.PutHWall(hyper.Point{11, 15})
+		m.PutVWall(hyper.Point{0, 6})
+		m.PutVWall(hyper.Point{15, 10})
+		start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		for i := range 366 {
+			day := start.AddDate(0, 0, i)
+			b, err := hyper.NewDailyBoard([]*hyper.Mapdata{m}, day, placement)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := hyper.NewPuzzle(b).Validate(); err != nil {
+				t.Errorf("%s: %v", day.Format(hyper.DAILY_DATE_FORMAT), err)
+			}
+		}
+	})
+
+	t.Run("no maps", func(t *testing.T) {
+		if _, err := hyper.NewDailyBoard(nil, date, hyper.Placement{}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestDailySeed(t *testing.T) {
+	testcases := []struct {
+		Date     time.Time
+		Expected int64
+	}{
+		{time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), 20240131},
+		{time.Date(2024, 12, 1, 23, 59, 59, 0, time.UTC), 20241201},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.Date.String(), func(t *testing.T) {
+			if actual := hyper.DailySeed(testcase.Date); actual != testcase.Expected {
+				t.Errorf("expected %d, but got %d", testcase.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestDailyScore_Share(t *testing.T) {
+	puzzle := &hyper.Puzzle{
+		Walls: [][]int{
+			{0, 0, 0, 0},
+			{0, 3, 1, 2},
+			{0, 2, 0, 3},
+			{0, 1, 3, 0},
+		},
+		Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+		Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+	}
+	b, err := puzzle.NewBoard(hyper.Placement{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Goal = hyper.Goal{Color: hyper.Red, Point: hyper.Point{0, 3}}
+	b.MoveActor(b.Actors[hyper.Blue], hyper.East)
+	b.MoveActor(b.Actors[hyper.Red], hyper.South)
+
+	s := hyper.NewDailyScore(time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), b)
+	expected := "Hyper Tux Daily 2024-01-31\nSolved in 2 steps\n🟦🟥"
+	if actual := s.Share(); actual != expected {
+		t.Errorf("expected %q, but got %q", expected, actual)
+	}
+}
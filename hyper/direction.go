@@ -1,5 +1,7 @@
 package hyper
 
+import "fmt"
+
 // Direction represents a direction in the game world.
 type Direction int
 
@@ -11,6 +13,9 @@ const (
 	South Direction = 8
 )
 
+// AllDirections is a slice containing all valid Direction values.
+var AllDirections = []Direction{North, West, East, South}
+
 // String returns the string representation of the direction.
 func (d Direction) String() string {
 	switch d {
@@ -25,3 +30,43 @@ func (d Direction) String() string {
 	}
 	return "unknown Direction"
 }
+
+// ParseDirection returns the Direction whose string representation is s.
+func ParseDirection(s string) (Direction, error) {
+	for _, d := range AllDirections {
+		if d.String() == s {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown direction: %s", s)
+}
+
+// MarshalText implements encoding.TextMarshaler so that directions are written by name in JSON.
+func (d Direction) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Direction) UnmarshalText(text []byte) error {
+	v, err := ParseDirection(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Vector returns the offset of a single step in the direction in grid coordinates.
+func (d Direction) Vector() Point {
+	switch d {
+	case North:
+		return Point{0, -1}
+	case West:
+		return Point{-1, 0}
+	case East:
+		return Point{1, 0}
+	case South:
+		return Point{0, 1}
+	}
+	return Point{}
+}
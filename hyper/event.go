@@ -0,0 +1,74 @@
+package hyper
+
+// EventKind represents the kind of an Event.
+type EventKind int
+
+// EventKind constants for changes of the board.
+const (
+	MovedEvent       EventKind = iota // an actor moved by Record
+	UndoneEvent                       // Record was undone
+	RedoneEvent                       // Record was redone
+	ResetEvent                        // all moves were undone
+	GoalReachedEvent                  // an actor reached the goal by Record
+	NewGameEvent                      // a new game started
+	JumpedEvent                       // the board jumped to the state after Record, or the initial state if nil
+)
+
+// String returns the string representation of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case MovedEvent:
+		return "Moved"
+	case UndoneEvent:
+		return "Undone"
+	case RedoneEvent:
+		return "Redone"
+	case ResetEvent:
+		return "Reset"
+	case GoalReachedEvent:
+		return "GoalReached"
+	case NewGameEvent:
+		return "NewGame"
+	case JumpedEvent:
+		return "Jumped"
+	}
+	return "unknown EventKind"
+}
+
+// Event notifies subscribers of a change of the board.
+// Record is nil for events which are not related to a single move.
+type Event struct {
+	Kind EventKind
+	*Record
+}
+
+// Subscriber is a function called with events of the board.
+type Subscriber func(e *Event)
+
+// subscription holds a Subscriber so that it can be identified on unsubscribing.
+type subscription struct {
+	f Subscriber
+}
+
+// Subscribe registers the subscriber to be called on every event of the board in order of subscription.
+// The returned function removes the subscriber.
+func (b *Board) Subscribe(f Subscriber) (unsubscribe func()) {
+	s := &subscription{f}
+	b.subscriptions = append(b.subscriptions, s)
+	return func() {
+		for i, other := range b.subscriptions {
+			if other == s {
+				b.subscriptions = append(b.subscriptions[:i:i], b.subscriptions[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// emit calls all subscribers with the event.
+func (b *Board) emit(kind EventKind, r *Record) {
+	e := &Event{Kind: kind, Record: r}
+	for _, s := range b.subscriptions {
+		s.f(e)
+	}
+}
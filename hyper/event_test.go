@@ -0,0 +1,131 @@
+package hyper_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+func TestBoard_Subscribe(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Goal     hyper.Point
+		Operate  func(b *hyper.Board)
+		Expected []hyper.EventKind
+	}{
+		{
+			"move",
+			hyper.Point{3, 3},
+			func(b *hyper.Board) {
+				b.MoveActor(b.Actors[hyper.Red], hyper.North)
+			},
+			[]hyper.EventKind{hyper.MovedEvent},
+		},
+		{
+			"unable to move",
+			hyper.Point{3, 3},
+			func(b *hyper.Board) {
+				b.MoveActor(b.Actors[hyper.Red], hyper.North)
+				b.MoveActor(b.Actors[hyper.Red], hyper.North)
+			},
+			[]hyper.EventKind{hyper.MovedEvent},
+		},
+		{
+			"reach goal",
+			hyper.Point{1, 0},
+			func(b *hyper.Board) {
+				b.MoveActor(b.Actors[hyper.Red], hyper.North)
+			},
+			[]hyper.EventKind{hyper.MovedEvent, hyper.GoalReachedEvent},
+		},
+		{
+			"undo and redo",
+			hyper.Point{1, 0},
+			func(b *hyper.Board) {
+				b.MoveActor(b.Actors[hyper.Red], hyper.North)
+				b.Undo()
+				b.Redo()
+			},
+			[]hyper.EventKind{hyper.MovedEvent, hyper.GoalReachedEvent, hyper.UndoneEvent, hyper.RedoneEvent, hyper.GoalReachedEvent},
+		},
+		{
+			"nothing to undo",
+			hyper.Point{3, 3},
+			func(b *hyper.Board) {
+				b.Undo()
+				b.Redo()
+			},
+			[]hyper.EventKind{},
+		},
+		{
+			"reset",
+			hyper.Point{3, 3},
+			func(b *hyper.Board) {
+				b.MoveActor(b.Actors[hyper.Red], hyper.North)
+				b.MoveActor(b.Actors[hyper.Red], hyper.West)
+				b.Reset()
+			},
+			[]hyper.EventKind{hyper.MovedEvent, hyper.MovedEvent, hyper.ResetEvent},
+		},
+		{
+			"new game",
+			hyper.Point{3, 3},
+			func(b *hyper.Board) {
+				// the goal cannot be placed on the current goal
+				b.Placement.Goal = hyper.PlaceGoalAt(hyper.Red, hyper.Point{4, 4})
+				b.NewGame()
+			},
+			[]hyper.EventKind{hyper.NewGameEvent},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			b, err := hyper.NewBoard(hyper.Size{16, 16}, hyper.Placement{
+				Actor: hyper.PlaceActorAt(defaultActorPlacement),
+				Goal:  hyper.PlaceGoalAt(hyper.Red, testcase.Goal),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual := []hyper.EventKind{}
+			b.Subscribe(func(e *hyper.Event) {
+				actual = append(actual, e.Kind)
+			})
+			testcase.Operate(b)
+			if !slices.Equal(testcase.Expected, actual) {
+				t.Errorf("unexpected value: Expected = %v, Actual = %v", testcase.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestBoard_Unsubscribe(t *testing.T) {
+	b, err := hyper.NewBoard(hyper.Size{16, 16}, hyper.Placement{
+		Actor: hyper.PlaceActorAt(defaultActorPlacement),
+		Goal:  hyper.PlaceGoalAt(hyper.Red, hyper.Point{3, 3}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, second := 0, 0
+	unsubscribe := b.Subscribe(func(e *hyper.Event) {
+		first++
+	})
+	b.Subscribe(func(e *hyper.Event) {
+		second++
+	})
+
+	b.MoveActor(b.Actors[hyper.Red], hyper.North)
+	unsubscribe()
+	b.MoveActor(b.Actors[hyper.Red], hyper.West)
+
+	if first != 1 {
+		t.Errorf("unexpected number of events of unsubscribed: Expected = 1, Actual = %d", first)
+	}
+	if second != 2 {
+		t.Errorf("unexpected number of events of subscribed: Expected = 2, Actual = %d", second)
+	}
+}
@@ -1,5 +1,7 @@
 package hyper
 
+import "encoding/json"
+
 // Goal represents a target location for actors to reach.
 type Goal struct {
 	Color
@@ -13,3 +15,25 @@ func (g Goal) Reached(actor Actor) bool {
 	posIsSame := g.Point.Equals(actor.Point)
 	return (colorIsSame && posIsSame)
 }
+
+// goalJSON is the JSON representation of Goal.
+// It is needed since Goal would be written as its color by the promoted MarshalText otherwise.
+type goalJSON struct {
+	Color Color
+	X, Y  int
+}
+
+// MarshalJSON implements json.Marshaler.
+func (g Goal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(goalJSON{g.Color, g.X, g.Y})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (g *Goal) UnmarshalJSON(data []byte) error {
+	v := goalJSON{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*g = Goal{v.Color, Point{v.X, v.Y}}
+	return nil
+}
@@ -15,49 +15,165 @@ func (r *Record) Equals(other *Record) bool {
 		r.End.Equals(other.End))
 }
 
+// Node is a move in the history tree.
+// Children are alternative moves following this one.
+type Node struct {
+	*Record        // nil for the root
+	Steps    int   // number of moves from the initial state
+	Parent   *Node // nil for the root
+	Children []*Node
+	active   int // index of the child followed by Redo
+}
+
+// Active returns the child followed by Redo, or nil if there is none.
+func (n *Node) Active() *Node {
+	if len(n.Children) == 0 {
+		return nil
+	}
+	return n.Children[n.active]
+}
+
+// Path returns the nodes from the root to this node, excluding the root.
+func (n *Node) Path() []*Node {
+	path := make([]*Node, n.Steps)
+	for node := n; node.Parent != nil; node = node.Parent {
+		path[node.Steps-1] = node
+	}
+	return path
+}
+
+// Root returns the root of the tree which this node belongs to.
+func (n *Node) Root() *Node {
+	node := n
+	for node.Parent != nil {
+		node = node.Parent
+	}
+	return node
+}
+
 // History manages undo and redo functionality for game moves.
+// Moves are kept as a tree so that alternative lines are not lost when a different move is made after undoing.
 type History struct {
-	records []*Record
-	last    int
+	root    *Node
+	current *Node
 }
 
-// Records returns all recorded moves.
+// init creates the root if the history is empty.
+func (h *History) init() {
+	if h.root == nil {
+		h.root = &Node{}
+		h.current = h.root
+	}
+}
+
+// Root returns the root of the history tree, which represents the initial state.
+func (h *History) Root() *Node {
+	h.init()
+	return h.root
+}
+
+// Current returns the node of the last move, or the root if no move is made.
+func (h *History) Current() *Node {
+	h.init()
+	return h.current
+}
+
+// Records returns the moves of the active line, including the ones which can be redone.
 func (h *History) Records() []*Record {
-	return h.records
+	h.init()
+	records := []*Record{}
+	for node := h.root.Active(); node != nil; node = node.Active() {
+		records = append(records, node.Record)
+	}
+	return records
 }
 
 // Push adds a new move record to the history.
+// A new branch is created unless the move is the same as one already made from the current state.
 func (h *History) Push(r *Record) {
-	h.records = append(h.records[:h.last], r)
-	h.last++
+	h.init()
+	for i, child := range h.current.Children {
+		if child.Record.Equals(r) {
+			h.current.active = i
+			h.current = child
+			return
+		}
+	}
+	child := &Node{
+		Record: r,
+		Steps:  h.current.Steps + 1,
+		Parent: h.current,
+	}
+	h.current.Children = append(h.current.Children, child)
+	h.current.active = len(h.current.Children) - 1
+	h.current = child
 }
 
 // Reset clears all move history.
 func (h *History) Reset() {
-	h.records = nil
-	h.last = 0
+	h.root = nil
+	h.current = nil
 }
 
-// Len returns the number of recorded moves.
+// Len returns the number of moves taken to the current state.
 func (h *History) Len() int {
-	return h.last
+	h.init()
+	return h.current.Steps
 }
 
 // Undo reverts the last move and returns it.
 func (h *History) Undo() *Record {
-	if h.last < 1 {
+	h.init()
+	if h.current.Parent == nil {
 		return nil
 	}
-	r := h.records[h.last-1]
-	h.last--
+	r := h.current.Record
+	h.current = h.current.Parent
 	return r
 }
 
-// Redo replays the next move in history and returns it.
+// Redo replays the next move of the active branch and returns it.
 func (h *History) Redo() *Record {
-	if h.last+1 <= len(h.records) {
-		h.last++
-		return h.records[h.last-1]
+	h.init()
+	next := h.current.Active()
+	if next == nil {
+		return nil
+	}
+	h.current = next
+	return next.Record
+}
+
+// JumpTo makes the node current and the line to it active.
+// It returns the records to be undone and then redone, in order, to get from the previous state to the node.
+// Nothing happens if the node does not belong to this history.
+func (h *History) JumpTo(n *Node) (undone, redone []*Record) {
+	h.init()
+	if n.Root() != h.root {
+		return nil, nil
+	}
+	path := n.Path()
+	// go back to the nearest common ancestor
+	for h.current.Steps > 0 && (h.current.Steps > n.Steps || path[h.current.Steps-1] != h.current) {
+		undone = append(undone, h.current.Record)
+		h.current = h.current.Parent
+	}
+	for _, node := range path[h.current.Steps:] {
+		for i, child := range node.Parent.Children {
+			if child == node {
+				node.Parent.active = i
+			}
+		}
+		redone = append(redone, node.Record)
+	}
+	h.current = n
+	return undone, redone
+}
+
+// Siblings returns the alternatives of the current move including itself, and the index of the current one.
+func (h *History) Siblings() ([]*Node, int) {
+	h.init()
+	if h.current.Parent == nil {
+		return []*Node{h.current}, 0
 	}
-	return nil
+	return h.current.Parent.Children, h.current.Parent.active
 }
@@ -58,3 +58,117 @@ func TestHistoryRedo(t *testing.T) {
 		}
 	})
 }
+
+func TestHistoryBranch(t *testing.T) {
+	a := &hyper.Record{Color: hyper.Red, Direction: hyper.North, Start: hyper.Point{1, 1}, End: hyper.Point{1, 0}}
+	b := &hyper.Record{Color: hyper.Red, Direction: hyper.East, Start: hyper.Point{1, 0}, End: hyper.Point{15, 0}}
+	c := &hyper.Record{Color: hyper.Red, Direction: hyper.West, Start: hyper.Point{1, 0}, End: hyper.Point{0, 0}}
+
+	testcases := []struct {
+		Name     string
+		Operate  func(h *hyper.History)
+		Expected []*hyper.Record
+		Len      int
+	}{
+		{
+			"keep the redo branch",
+			func(h *hyper.History) {
+				h.Push(a)
+				h.Push(b)
+				h.Undo()
+				h.Push(c)
+			},
+			[]*hyper.Record{a, c},
+			2,
+		},
+		{
+			"reuse the same move",
+			func(h *hyper.History) {
+				h.Push(a)
+				h.Push(b)
+				h.Undo()
+				h.Undo()
+				h.Push(a)
+			},
+			[]*hyper.Record{a, b},
+			1,
+		},
+		{
+			"jump to the previous branch",
+			func(h *hyper.History) {
+				h.Push(a)
+				h.Push(b)
+				h.Undo()
+				h.Push(c)
+				undone, redone := h.JumpTo(h.Root().Children[0].Children[0])
+				if len(undone) != 1 || undone[0] != c || len(redone) != 1 || redone[0] != b {
+					t.Errorf("unexpected records: undone = %+v, redone = %+v", undone, redone)
+				}
+			},
+			[]*hyper.Record{a, b},
+			2,
+		},
+		{
+			"jump to the root",
+			func(h *hyper.History) {
+				h.Push(a)
+				h.Push(b)
+				undone, redone := h.JumpTo(h.Root())
+				if len(undone) != 2 || len(redone) != 0 {
+					t.Errorf("unexpected records: undone = %+v, redone = %+v", undone, redone)
+				}
+			},
+			[]*hyper.Record{a, b},
+			0,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			h := &hyper.History{}
+			testcase.Operate(h)
+			actual := h.Records()
+			if len(actual) != len(testcase.Expected) {
+				t.Fatalf("unexpected records: Expected = %+v, Actual = %+v", testcase.Expected, actual)
+			}
+			for i := range actual {
+				if !actual[i].Equals(testcase.Expected[i]) {
+					t.Errorf("difference at %d:\n\texpected = %+v\n\t  actual = %+v", i, testcase.Expected[i], actual[i])
+				}
+			}
+			if h.Len() != testcase.Len {
+				t.Errorf("unexpected length: Expected = %d, Actual = %d", testcase.Len, h.Len())
+			}
+		})
+	}
+}
+
+func TestBoard_SwitchBranch(t *testing.T) {
+	b, err := hyper.NewBoard(hyper.Size{16, 16}, hyper.Placement{
+		Actor: hyper.PlaceActorAt(defaultActorPlacement),
+		Goal:  hyper.PlaceGoalAt(hyper.Red, hyper.Point{3, 3}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	red := b.Actors[hyper.Red]
+
+	b.MoveActor(red, hyper.North)
+	north := red.Point
+	b.Undo()
+	b.MoveActor(red, hyper.West)
+	west := red.Point
+
+	if !b.SwitchBranch(1) {
+		t.Fatal("unable to switch")
+	}
+	if !red.Point.Equals(north) {
+		t.Errorf("unexpected position: Expected = %+v, Actual = %+v", north, red.Point)
+	}
+	if !b.SwitchBranch(1) {
+		t.Fatal("unable to switch")
+	}
+	if !red.Point.Equals(west) {
+		t.Errorf("unexpected position: Expected = %+v, Actual = %+v", west, red.Point)
+	}
+}
@@ -2,6 +2,7 @@ package hyper
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -10,6 +11,16 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+// MIN_BOARD_SIZE is the minimum width and height of boards, which is the size of the center box.
+const MIN_BOARD_SIZE = 2
+
+// Errors returned by invalid edits of Mapdata.
+var (
+	ErrOutOfBoard  = errors.New("out of the board")
+	ErrCenterWall  = errors.New("wall of the center box")
+	ErrInvalidSize = errors.New("invalid size")
+)
+
 // walls
 // - - - - - -
 // -|-|-|-|-|-
@@ -69,6 +80,12 @@ func NewMapdataFromSlice(rows [][]int) (*Mapdata, error) {
 		return NewMapdata(Size{0, 0}), nil
 	}
 
+	for y, row := range rows {
+		if len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("%w: row %d has %d cells instead of %d", ErrInvalidSize, y, len(row), len(rows[0]))
+		}
+	}
+
 	m := NewMapdata(Size{W: len(rows[0]), H: len(rows)})
 
 	for y, row := range rows {
@@ -89,18 +106,247 @@ func NewMapdataFromSlice(rows [][]int) (*Mapdata, error) {
 }
 
 // PutHWall adds a horizontal wall at the given position.
-func (m *Mapdata) PutHWall(p Point) {
-	if slices.Contains(m.HWalls[p.X], p.Y) {
-		return
+func (m *Mapdata) PutHWall(p Point) error {
+	if !m.contains(p) {
+		return fmt.Errorf("%w: %v", ErrOutOfBoard, p)
+	}
+	if !slices.Contains(m.HWalls[p.X], p.Y) {
+		m.HWalls[p.X] = append(m.HWalls[p.X], p.Y)
+	}
+	return nil
+}
+
+// PutVWall adds a vertical wall at the given position.
+func (m *Mapdata) PutVWall(p Point) error {
+	if !m.contains(p) {
+		return fmt.Errorf("%w: %v", ErrOutOfBoard, p)
+	}
+	if !slices.Contains(m.VWalls[p.Y], p.X) {
+		m.VWalls[p.Y] = append(m.VWalls[p.Y], p.X)
+	}
+	return nil
+}
+
+// RemoveHWall removes the horizontal wall at the given position, if any.
+// Walls of the center box cannot be removed.
+func (m *Mapdata) RemoveHWall(p Point) error {
+	if !m.contains(p) {
+		return fmt.Errorf("%w: %v", ErrOutOfBoard, p)
+	}
+	if m.IsCenterHWall(p) {
+		return fmt.Errorf("%w: %v", ErrCenterWall, p)
+	}
+	m.removeHWall(p)
+	return nil
+}
+
+// RemoveVWall removes the vertical wall at the given position, if any.
+// Walls of the center box cannot be removed.
+func (m *Mapdata) RemoveVWall(p Point) error {
+	if !m.contains(p) {
+		return fmt.Errorf("%w: %v", ErrOutOfBoard, p)
+	}
+	if m.IsCenterVWall(p) {
+		return fmt.Errorf("%w: %v", ErrCenterWall, p)
 	}
-	m.HWalls[p.X] = append(m.HWalls[p.X], p.Y)
+	m.removeVWall(p)
+	return nil
 }
 
-func (m *Mapdata) PutVWall(p Point) {
-	if slices.Contains(m.VWalls[p.Y], p.X) {
-		return
+func (m *Mapdata) removeHWall(p Point) {
+	if i := slices.Index(m.HWalls[p.X], p.Y); i >= 0 {
+		m.HWalls[p.X] = slices.Delete(m.HWalls[p.X], i, i+1)
 	}
-	m.VWalls[p.Y] = append(m.VWalls[p.Y], p.X)
+}
+
+func (m *Mapdata) removeVWall(p Point) {
+	if i := slices.Index(m.VWalls[p.Y], p.X); i >= 0 {
+		m.VWalls[p.Y] = slices.Delete(m.VWalls[p.Y], i, i+1)
+	}
+}
+
+// ToggleHWall puts the horizontal wall at the given position, or removes it if exists.
+// It returns true if the wall exists after toggling.
+func (m *Mapdata) ToggleHWall(p Point) (bool, error) {
+	if !m.contains(p) {
+		return false, fmt.Errorf("%w: %v", ErrOutOfBoard, p)
+	}
+	if m.HasHWall(p) {
+		return false, m.RemoveHWall(p)
+	}
+	return true, m.PutHWall(p)
+}
+
+// ToggleVWall puts the vertical wall at the given position, or removes it if exists.
+// It returns true if the wall exists after toggling.
+func (m *Mapdata) ToggleVWall(p Point) (bool, error) {
+	if !m.contains(p) {
+		return false, fmt.Errorf("%w: %v", ErrOutOfBoard, p)
+	}
+	if m.HasVWall(p) {
+		return false, m.RemoveVWall(p)
+	}
+	return true, m.PutVWall(p)
+}
+
+// HasHWall returns true if there is a horizontal wall on the north edge of the cell at the given position.
+func (m *Mapdata) HasHWall(p Point) bool {
+	return 0 <= p.X && p.X < len(m.HWalls) && slices.Contains(m.HWalls[p.X], p.Y)
+}
+
+// HasVWall returns true if there is a vertical wall on the west edge of the cell at the given position.
+func (m *Mapdata) HasVWall(p Point) bool {
+	return 0 <= p.Y && p.Y < len(m.VWalls) && slices.Contains(m.VWalls[p.Y], p.X)
+}
+
+// IsCenterHWall returns true if the horizontal wall at the given position is a wall of the center box.
+func (m *Mapdata) IsCenterHWall(p Point) bool {
+	hwalls, _ := m.centerWalls()
+	return slices.Contains(hwalls, p)
+}
+
+// IsCenterVWall returns true if the vertical wall at the given position is a wall of the center box.
+func (m *Mapdata) IsCenterVWall(p Point) bool {
+	_, vwalls := m.centerWalls()
+	return slices.Contains(vwalls, p)
+}
+
+// contains returns true if the cell at the given position is on the board.
+func (m *Mapdata) contains(p Point) bool {
+	r := NewRect(Point{0, 0}, m.Size)
+	return r.Contains(p)
+}
+
+// Clone returns a deep copy of the mapdata.
+func (m *Mapdata) Clone() *Mapdata {
+	c := &Mapdata{
+		Size:   m.Size,
+		HWalls: make([][]int, len(m.HWalls)),
+		VWalls: make([][]int, len(m.VWalls)),
+	}
+	for x, ys := range m.HWalls {
+		c.HWalls[x] = slices.Clone(ys)
+	}
+	for y, xs := range m.VWalls {
+		c.VWalls[y] = slices.Clone(xs)
+	}
+	return c
+}
+
+// Copy returns the wall bits of each cell in the region in the format accepted by NewMapdataFromSlice and Paste.
+func (m *Mapdata) Copy(r Rect) ([][]int, error) {
+	if err := m.checkRegion(r); err != nil {
+		return nil, err
+	}
+	return m.region(r), nil
+}
+
+// Paste replaces the walls of the region whose top-left corner is at the given position with the wall bits of each cell.
+// Walls of the center box are kept as they are.
+func (m *Mapdata) Paste(p Point, rows [][]int) error {
+	if len(rows) < 1 || len(rows[0]) < 1 {
+		return fmt.Errorf("%w: nothing to paste", ErrInvalidSize)
+	}
+	for y, row := range rows {
+		if len(row) != len(rows[0]) {
+			return fmt.Errorf("%w: row %d has %d cells instead of %d", ErrInvalidSize, y, len(row), len(rows[0]))
+		}
+	}
+	if err := m.checkRegion(NewRect(p, Size{W: len(rows[0]), H: len(rows)})); err != nil {
+		return err
+	}
+
+	for y, row := range rows {
+		for x, n := range row {
+			cell := p.Add(Point{x, y})
+			m.removeHWall(cell)
+			m.removeVWall(cell)
+			if (n & int(North)) != 0 {
+				m.PutHWall(cell)
+			}
+			if (n & int(West)) != 0 {
+				m.PutVWall(cell)
+			}
+		}
+	}
+	m.initCenterWalls()
+	return nil
+}
+
+// Resize makes the region of the board the new board.
+// Walls out of the region are cropped, and parts of the region out of the board are padded with cells without walls.
+// The center box is moved to the center of the new board.
+func (m *Mapdata) Resize(r Rect) error {
+	size := r.Size()
+	if size.W < MIN_BOARD_SIZE || size.H < MIN_BOARD_SIZE {
+		return fmt.Errorf("%w: %dx%d", ErrInvalidSize, size.W, size.H)
+	}
+	c := m.Clone()
+	c.removeCenterWalls()
+	resized, err := NewMapdataFromSlice(c.region(r))
+	if err != nil {
+		return err
+	}
+	*m = *resized
+	return nil
+}
+
+// checkRegion returns an error if the region is empty or not on the board.
+func (m *Mapdata) checkRegion(r Rect) error {
+	size := r.Size()
+	if size.W < 1 || size.H < 1 {
+		return fmt.Errorf("%w: %dx%d", ErrInvalidSize, size.W, size.H)
+	}
+	if !m.contains(r.TopLeft) || !m.contains(r.BottomRight.Sub(Point{1, 1})) {
+		return fmt.Errorf("%w: %v-%v", ErrOutOfBoard, r.TopLeft, r.BottomRight)
+	}
+	return nil
+}
+
+// region returns the wall bits of each cell in the region, where cells out of the board have no walls.
+func (m *Mapdata) region(r Rect) [][]int {
+	size := r.Size()
+	rows := make([][]int, size.H)
+	for y := range rows {
+		rows[y] = make([]int, size.W)
+		for x := range rows[y] {
+			cell := r.TopLeft.Add(Point{x, y})
+			if !m.contains(cell) {
+				continue
+			}
+			if m.HasHWall(cell) {
+				rows[y][x] |= int(North)
+			}
+			if m.HasVWall(cell) {
+				rows[y][x] |= int(West)
+			}
+		}
+	}
+	return rows
+}
+
+// ToSlice returns the wall bits of each cell in the format accepted by NewMapdataFromSlice.
+// Walls on the south and east borders of the board are not included.
+func (m *Mapdata) ToSlice() [][]int {
+	rows := make([][]int, m.H)
+	for y := range rows {
+		rows[y] = make([]int, m.W)
+	}
+	for x, ys := range m.HWalls {
+		for _, y := range ys {
+			if y < m.H {
+				rows[y][x] |= int(North)
+			}
+		}
+	}
+	for y, xs := range m.VWalls {
+		for _, x := range xs {
+			if x < m.W {
+				rows[y][x] |= int(West)
+			}
+		}
+	}
+	return rows
 }
 
 // Center returns a rectangle representing the center region of the board.
@@ -109,17 +355,42 @@ func (m *Mapdata) Center() Rect {
 	return NewRect(Point{c.X - 1, c.Y - 1}, Size{2, 2})
 }
 
-func (m *Mapdata) initCenterWalls() {
+// centerWalls returns the positions of the horizontal and vertical walls of the center box.
+func (m *Mapdata) centerWalls() (hwalls, vwalls []Point) {
 	r := m.Center()
+	hwalls = []Point{
+		{r.TopLeft.X, r.TopLeft.Y},
+		{r.TopLeft.X, r.BottomRight.Y},
+		{r.BottomRight.X - 1, r.TopLeft.Y},
+		{r.BottomRight.X - 1, r.BottomRight.Y},
+	}
+	vwalls = []Point{
+		{r.TopLeft.X, r.TopLeft.Y},
+		{r.TopLeft.X, r.BottomRight.Y - 1},
+		{r.BottomRight.X, r.TopLeft.Y},
+		{r.BottomRight.X, r.BottomRight.Y - 1},
+	}
+	return
+}
+
+func (m *Mapdata) initCenterWalls() {
+	hwalls, vwalls := m.centerWalls()
+	for _, p := range hwalls {
+		m.PutHWall(p)
+	}
+	for _, p := range vwalls {
+		m.PutVWall(p)
+	}
+}
 
-	m.PutHWall(Point{r.TopLeft.X, r.TopLeft.Y})
-	m.PutVWall(Point{r.TopLeft.X, r.TopLeft.Y})
-	m.PutHWall(Point{r.TopLeft.X, r.BottomRight.Y})
-	m.PutVWall(Point{r.TopLeft.X, r.BottomRight.Y - 1})
-	m.PutHWall(Point{r.BottomRight.X - 1, r.TopLeft.Y})
-	m.PutVWall(Point{r.BottomRight.X, r.TopLeft.Y})
-	m.PutHWall(Point{r.BottomRight.X - 1, r.BottomRight.Y})
-	m.PutVWall(Point{r.BottomRight.X, r.BottomRight.Y - 1})
+func (m *Mapdata) removeCenterWalls() {
+	hwalls, vwalls := m.centerWalls()
+	for _, p := range hwalls {
+		m.removeHWall(p)
+	}
+	for _, p := range vwalls {
+		m.removeVWall(p)
+	}
 }
 
 // Equals returns true if both mapdatas have the same walls and dimensions.
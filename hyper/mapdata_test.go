@@ -1,6 +1,8 @@
 package hyper_test
 
 import (
+	"errors"
+	"slices"
 	"testing"
 
 	"github.com/fj68/hyper-tux-go/hyper"
@@ -49,3 +51,335 @@ func TestNewMapdataFromSlice(t *testing.T) {
 		})
 	}
 }
+
+// newEditedMapdata returns the 8x8 mapdata with walls on the north and west edges of (1, 1) and the west edge of (6, 6).
+func newEditedMapdata() *hyper.Mapdata {
+	m := hyper.NewMapdata(hyper.Size{8, 8})
+	m.PutHWall(hyper.Point{1, 1})
+	m.PutVWall(hyper.Point{1, 1})
+	m.PutVWall(hyper.Point{6, 6})
+	return m
+}
+
+// assertWalls fails the test if the mapdata does not have the walls of the expected wall bits, regardless of the order of walls.
+func assertWalls(t *testing.T, expected [][]int, actual *hyper.Mapdata) {
+	t.Helper()
+	m, err := hyper.NewMapdataFromSlice(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Size.Equals(actual.Size) || !slices.EqualFunc(m.ToSlice(), actual.ToSlice(), slices.Equal) {
+		t.Errorf("expected:\n\t%v\nactual:\n\t%v\n", m.ToSlice(), actual.ToSlice())
+	}
+}
+
+func TestMapdata_RemoveWall(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Operate  func(m *hyper.Mapdata) error
+		Expected func() *hyper.Mapdata
+		Err      error
+	}{
+		{
+			"remove horizontal wall",
+			func(m *hyper.Mapdata) error {
+				return m.RemoveHWall(hyper.Point{1, 1})
+			},
+			func() *hyper.Mapdata {
+				m := hyper.NewMapdata(hyper.Size{8, 8})
+				m.PutVWall(hyper.Point{1, 1})
+				m.PutVWall(hyper.Point{6, 6})
+				return m
+			},
+			nil,
+		},
+		{
+			"remove vertical wall",
+			func(m *hyper.Mapdata) error {
+				return m.RemoveVWall(hyper.Point{1, 1})
+			},
+			func() *hyper.Mapdata {
+				m := hyper.NewMapdata(hyper.Size{8, 8})
+				m.PutHWall(hyper.Point{1, 1})
+				m.PutVWall(hyper.Point{6, 6})
+				return m
+			},
+			nil,
+		},
+		{
+			"remove nothing",
+			func(m *hyper.Mapdata) error {
+				if err := m.RemoveHWall(hyper.Point{2, 2}); err != nil {
+					return err
+				}
+				return m.RemoveVWall(hyper.Point{6, 0})
+			},
+			newEditedMapdata,
+			nil,
+		},
+		{
+			"remove wall of the center box",
+			func(m *hyper.Mapdata) error {
+				return m.RemoveHWall(hyper.Point{3, 3})
+			},
+			newEditedMapdata,
+			hyper.ErrCenterWall,
+		},
+		{
+			"remove wall out of the board",
+			func(m *hyper.Mapdata) error {
+				return m.RemoveVWall(hyper.Point{8, 0})
+			},
+			newEditedMapdata,
+			hyper.ErrOutOfBoard,
+		},
+		{
+			"put horizontal wall out of the board",
+			func(m *hyper.Mapdata) error {
+				return m.PutHWall(hyper.Point{0, 8})
+			},
+			newEditedMapdata,
+			hyper.ErrOutOfBoard,
+		},
+		{
+			"put vertical wall out of the board",
+			func(m *hyper.Mapdata) error {
+				return m.PutVWall(hyper.Point{-1, 0})
+			},
+			newEditedMapdata,
+			hyper.ErrOutOfBoard,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			m := newEditedMapdata()
+			if err := testcase.Operate(m); !errors.Is(err, testcase.Err) {
+				t.Errorf("expected error %v, but got %v", testcase.Err, err)
+			}
+			expected := testcase.Expected()
+			if !expected.Equals(m) {
+				t.Errorf("expected:\n\t%+v\nactual:\n\t%+v\n", expected, m)
+			}
+		})
+	}
+}
+
+func TestMapdata_ToggleWall(t *testing.T) {
+	testcases := []struct {
+		Name       string
+		Point      hyper.Point
+		Horizontal bool
+		Exists     bool
+		Err        error
+	}{
+		{"put horizontal wall", hyper.Point{2, 5}, true, true, nil},
+		{"remove horizontal wall", hyper.Point{1, 1}, true, false, nil},
+		{"put vertical wall", hyper.Point{2, 5}, false, true, nil},
+		{"remove vertical wall", hyper.Point{6, 6}, false, false, nil},
+		{"wall of the center box", hyper.Point{5, 3}, false, false, hyper.ErrCenterWall},
+		{"out of the board", hyper.Point{0, -1}, true, false, hyper.ErrOutOfBoard},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			m := newEditedMapdata()
+			toggle, has := m.ToggleVWall, m.HasVWall
+			if testcase.Horizontal {
+				toggle, has = m.ToggleHWall, m.HasHWall
+			}
+			exists, err := toggle(testcase.Point)
+			if !errors.Is(err, testcase.Err) {
+				t.Errorf("expected error %v, but got %v", testcase.Err, err)
+			}
+			if exists != testcase.Exists {
+				t.Errorf("expected %v to be returned, but got %v", testcase.Exists, exists)
+			}
+			if err == nil && has(testcase.Point) != testcase.Exists {
+				t.Errorf("expected the wall to exist: %v", testcase.Exists)
+			}
+		})
+	}
+}
+
+func TestMapdata_Resize(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Region   hyper.Rect
+		Expected [][]int
+		Err      error
+	}{
+		{
+			"crop",
+			hyper.Rect{hyper.Point{1, 1}, hyper.Point{7, 7}},
+			[][]int{
+				{3, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 2},
+			},
+			nil,
+		},
+		{
+			"pad",
+			hyper.Rect{hyper.Point{-1, -1}, hyper.Point{9, 9}},
+			[][]int{
+				{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 3, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 2, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			},
+			nil,
+		},
+		{
+			"too small",
+			hyper.Rect{hyper.Point{0, 0}, hyper.Point{1, 8}},
+			nil,
+			hyper.ErrInvalidSize,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			m := newEditedMapdata()
+			err := m.Resize(testcase.Region)
+			if !errors.Is(err, testcase.Err) {
+				t.Fatalf("expected error %v, but got %v", testcase.Err, err)
+			}
+			if err != nil {
+				assertWalls(t, newEditedMapdata().ToSlice(), m)
+				return
+			}
+			assertWalls(t, testcase.Expected, m)
+		})
+	}
+}
+
+func TestMapdata_CopyAndPaste(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Operate  func(m *hyper.Mapdata) error
+		Expected [][]int
+		Err      error
+	}{
+		{
+			"copy and paste",
+			func(m *hyper.Mapdata) error {
+				rows, err := m.Copy(hyper.Rect{hyper.Point{0, 0}, hyper.Point{3, 3}})
+				if err != nil {
+					return err
+				}
+				return m.Paste(hyper.Point{5, 0}, rows)
+			},
+			[][]int{
+				{0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 3, 0, 0, 0, 0, 3, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 2, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0},
+			},
+			nil,
+		},
+		{
+			"paste over walls and the center box",
+			func(m *hyper.Mapdata) error {
+				rows, err := m.Copy(hyper.Rect{hyper.Point{2, 0}, hyper.Point{7, 1}})
+				if err != nil {
+					return err
+				}
+				return m.Paste(hyper.Point{2, 3}, [][]int{rows[0], rows[0], rows[0], rows[0]})
+			},
+			[][]int{
+				{0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 3, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0},
+				{0, 0, 0, 0, 0, 0, 0, 0},
+			},
+			nil,
+		},
+		{
+			"copy out of the board",
+			func(m *hyper.Mapdata) error {
+				_, err := m.Copy(hyper.Rect{hyper.Point{6, 6}, hyper.Point{9, 9}})
+				return err
+			},
+			nil,
+			hyper.ErrOutOfBoard,
+		},
+		{
+			"paste out of the board",
+			func(m *hyper.Mapdata) error {
+				return m.Paste(hyper.Point{7, 0}, [][]int{{0, 0}})
+			},
+			nil,
+			hyper.ErrOutOfBoard,
+		},
+		{
+			"paste rows of different lengths",
+			func(m *hyper.Mapdata) error {
+				return m.Paste(hyper.Point{0, 0}, [][]int{{0, 0}, {0}})
+			},
+			nil,
+			hyper.ErrInvalidSize,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			m := newEditedMapdata()
+			err := testcase.Operate(m)
+			if !errors.Is(err, testcase.Err) {
+				t.Fatalf("expected error %v, but got %v", testcase.Err, err)
+			}
+			if err != nil {
+				assertWalls(t, newEditedMapdata().ToSlice(), m)
+				return
+			}
+			assertWalls(t, testcase.Expected, m)
+		})
+	}
+}
+
+func TestMapdata_ToSlice(t *testing.T) {
+	input := [][]int{
+		{0, 0, 0, 0, 0, 0},
+		{0, 3, 0, 0, 0, 0},
+		{0, 0, 3, 1, 2, 0},
+		{0, 0, 2, 0, 2, 0},
+		{0, 0, 1, 1, 0, 1},
+		{2, 0, 0, 0, 0, 0},
+	}
+	m, err := hyper.NewMapdataFromSlice(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := m.ToSlice(); !slices.EqualFunc(input, actual, slices.Equal) {
+		t.Errorf("expected:\n\t%v\nactual:\n\t%v\n", input, actual)
+	}
+}
+
+func TestNewMapdataFromSlice_InvalidSize(t *testing.T) {
+	_, err := hyper.NewMapdataFromSlice([][]int{
+		{0, 0, 0},
+		{0, 0},
+		{0, 0, 0},
+	})
+	if !errors.Is(err, hyper.ErrInvalidSize) {
+		t.Errorf("expected error %v, but got %v", hyper.ErrInvalidSize, err)
+	}
+}
@@ -0,0 +1,86 @@
+package hyper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// PackPuzzle is a puzzle of a Pack with the number of moves to aim at.
+type PackPuzzle struct {
+	Puzzle
+	Par  int    // number of moves of the intended solution
+	Hint string `json:",omitempty"`
+}
+
+// Pack is a curated series of puzzles, which are played in order.
+type Pack struct {
+	Title   string
+	Author  string
+	Puzzles []*PackPuzzle
+}
+
+// ReadPack decodes a pack from JSON and validates its puzzles.
+func ReadPack(r io.Reader) (*Pack, error) {
+	p := &Pack{}
+	if err := json.NewDecoder(r).Decode(p); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Write encodes the pack as JSON.
+func (p *Pack) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// Validate returns an error if the pack has no title or puzzles, or any of its puzzles is invalid.
+func (p *Pack) Validate() error {
+	if p.Title == "" {
+		return errors.New("title of the pack is missing")
+	}
+	if len(p.Puzzles) == 0 {
+		return fmt.Errorf("%s: no puzzles", p.Title)
+	}
+	for i, puzzle := range p.Puzzles {
+		if puzzle == nil {
+			return fmt.Errorf("%s: puzzle %d is missing", p.Title, i+1)
+		}
+		if err := puzzle.Validate(); err != nil {
+			return fmt.Errorf("%s: puzzle %d: %w", p.Title, i+1, err)
+		}
+		if puzzle.Par < 1 {
+			return fmt.Errorf("%s: puzzle %d: invalid par: %d", p.Title, i+1, puzzle.Par)
+		}
+	}
+	return nil
+}
+
+// PackProgress is the fewest moves to solve each puzzle of a pack by its index, which is missing for unsolved puzzles.
+type PackProgress map[int]int
+
+// Solved reports whether the i-th puzzle is solved.
+func (p PackProgress) Solved(i int) bool {
+	_, ok := p[i]
+	return ok
+}
+
+// Unlocked reports whether the i-th puzzle can be played, that is, it is the first one or the previous one is solved.
+func (p PackProgress) Unlocked(i int) bool {
+	return i == 0 || p.Solved(i-1)
+}
+
+// Record records the i-th puzzle solved in the moves, and reports whether it is the fewest so far.
+func (p PackProgress) Record(i, moves int) bool {
+	if best, ok := p[i]; ok && best <= moves {
+		return false
+	}
+	p[i] = moves
+	return true
+}
@@ -0,0 +1,98 @@
+package hyper_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+func TestReadPack(t *testing.T) {
+	puzzle := hyper.Puzzle{
+		Walls: [][]int{
+			{0, 0, 0, 0},
+			{0, 3, 1, 2},
+			{0, 2, 0, 3},
+			{0, 1, 3, 0},
+		},
+		Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+		Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+	}
+	expected := &hyper.Pack{
+		Title:  "Test",
+		Author: "Tux",
+		Puzzles: []*hyper.PackPuzzle{
+			{Puzzle: puzzle, Par: 2, Hint: "Blue first"},
+			{Puzzle: hyper.Puzzle{Walls: puzzle.Walls, Actors: puzzle.Actors, Goal: hyper.Goal{Color: hyper.Blue, Point: hyper.Point{1, 3}}}, Par: 1},
+		},
+	}
+	buf := &bytes.Buffer{}
+	if err := expected.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := hyper.ReadPack(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v, but got %+v", expected, actual)
+	}
+}
+
+func TestPack_Validate(t *testing.T) {
+	puzzle := hyper.Puzzle{
+		Walls: [][]int{
+			{0, 0, 0, 0},
+			{0, 3, 1, 2},
+			{0, 2, 0, 3},
+			{0, 1, 3, 0},
+		},
+		Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+		Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+	}
+	invalid := hyper.Puzzle{Walls: puzzle.Walls, Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}}, Goal: puzzle.Goal}
+
+	testcases := []struct {
+		Name  string
+		Pack  *hyper.Pack
+		Valid bool
+	}{
+		{"valid", &hyper.Pack{Title: "Test", Author: "Tux", Puzzles: []*hyper.PackPuzzle{{Puzzle: puzzle, Par: 2}}}, true},
+		{"no title", &hyper.Pack{Author: "Tux", Puzzles: []*hyper.PackPuzzle{{Puzzle: puzzle, Par: 2}}}, false},
+		{"no author", &hyper.Pack{Title: "Test", Puzzles: []*hyper.PackPuzzle{{Puzzle: puzzle, Par: 2}}}, true},
+		{"no puzzles", &hyper.Pack{Title: "Test", Author: "Tux"}, false},
+		{"missing puzzle", &hyper.Pack{Title: "Test", Author: "Tux", Puzzles: []*hyper.PackPuzzle{{Puzzle: puzzle, Par: 2}, nil}}, false},
+		{"invalid puzzle", &hyper.Pack{Title: "Test", Author: "Tux", Puzzles: []*hyper.PackPuzzle{{Puzzle: puzzle, Par: 2}, {Puzzle: invalid, Par: 1}}}, false},
+		{"no par", &hyper.Pack{Title: "Test", Author: "Tux", Puzzles: []*hyper.PackPuzzle{{Puzzle: puzzle}}}, false},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			if err := testcase.Pack.Validate(); (err == nil) != testcase.Valid {
+				t.Errorf("expected valid = %v, but got error %v", testcase.Valid, err)
+			}
+		})
+	}
+}
+
+func TestPackProgress(t *testing.T) {
+	p := hyper.PackProgress{}
+	if !p.Unlocked(0) || p.Unlocked(1) {
+		t.Errorf("expected only the first puzzle to be unlocked: %+v", p)
+	}
+	if !p.Record(0, 5) {
+		t.Error("expected the first record to be the best")
+	}
+	if p.Record(0, 6) {
+		t.Error("expected more moves not to be the best")
+	}
+	if !p.Record(0, 4) {
+		t.Error("expected fewer moves to be the best")
+	}
+	if !p.Solved(0) || p[0] != 4 {
+		t.Errorf("expected the first puzzle solved in 4 moves: %+v", p)
+	}
+	if !p.Unlocked(1) || p.Unlocked(2) {
+		t.Errorf("expected the second puzzle to be unlocked next: %+v", p)
+	}
+}
@@ -2,7 +2,6 @@ package hyper
 
 import (
 	"maps"
-	"math/rand"
 )
 
 // PlacementAlgorithm defines a function type for placing actors and goals on the board.
@@ -51,7 +50,7 @@ func PlaceActorAtRandom(b *Board, _ Color) (Point, bool) {
 
 // PlaceGoalAtRandom returns a random point and color for the goal.
 func PlaceGoalAtRandom(b *Board) (Goal, bool) {
-	return Goal{ColorAtRandom(), PlaceAtRandom(b)}, true
+	return Goal{b.colorAtRandom(), PlaceAtRandom(b)}, true
 }
 
 // PlaceNearByWalls returns a point near existing walls on the board.
@@ -70,8 +69,8 @@ func PlaceNearByWalls(b *Board) (Point, bool) {
 	if len(walls) == 0 {
 		return Point{}, false
 	}
-	wall := walls[rand.Intn(len(walls))]
-	return wall.Add(Point{X: rand.Intn(2) - 1, Y: rand.Intn(2) - 1}), true
+	wall := walls[b.rand.Intn(len(walls))]
+	return wall.Add(Point{X: b.rand.Intn(2) - 1, Y: b.rand.Intn(2) - 1}), true
 }
 
 // PlaceActorNearByWalls returns a point near existing walls on the board.
@@ -82,7 +81,7 @@ func PlaceActorNearByWalls(b *Board, _ Color) (Point, bool) {
 // PlaceGoalNearByWalls returns a point near existing walls on the board along with a random color.
 func PlaceGoalNearByWalls(b *Board) (Goal, bool) {
 	if pos, ok := PlaceNearByWalls(b); ok {
-		return Goal{ColorAtRandom(), pos}, true
+		return Goal{b.colorAtRandom(), pos}, true
 	}
 	return b.Goal, false
 }
@@ -0,0 +1,111 @@
+package hyper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Puzzle is a board in its initial state: walls, positions of actors and the goal.
+type Puzzle struct {
+	Walls  [][]int // wall bits of each cell in the format accepted by NewMapdataFromSlice
+	Actors map[Color]Point
+	Goal   Goal
+}
+
+// NewPuzzle returns the puzzle of the board, with actors at their positions before the moves in history.
+func NewPuzzle(b *Board) *Puzzle {
+	return &Puzzle{
+		Walls:  b.Mapdata.ToSlice(),
+		Actors: b.InitialActors(),
+		Goal:   b.Goal,
+	}
+}
+
+// ReadPuzzle decodes a puzzle from JSON and validates it.
+func ReadPuzzle(r io.Reader) (*Puzzle, error) {
+	p := &Puzzle{}
+	if err := json.NewDecoder(r).Decode(p); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Write encodes the puzzle as JSON.
+func (p *Puzzle) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// Size returns the size of the board of the puzzle.
+func (p *Puzzle) Size() Size {
+	if len(p.Walls) == 0 {
+		return Size{0, 0}
+	}
+	return Size{W: len(p.Walls[0]), H: len(p.Walls)}
+}
+
+// Validate returns an error if the board of the puzzle is not rectangular,
+// or if actors and the goal are missing, out of the board, in the center or overlapping.
+func (p *Puzzle) Validate() error {
+	size := p.Size()
+	if size.W < 2 || size.H < 2 {
+		return fmt.Errorf("board is too small: %dx%d", size.W, size.H)
+	}
+	for y, row := range p.Walls {
+		if len(row) != size.W {
+			return fmt.Errorf("row %d has %d cells instead of %d", y, len(row), size.W)
+		}
+	}
+
+	m := NewMapdata(size)
+	board := NewRect(Point{0, 0}, size)
+	center := m.Center()
+	occupied := map[Point]Color{}
+	for _, c := range AllColors {
+		pos, ok := p.Actors[c]
+		if !ok {
+			return fmt.Errorf("%s actor is missing", c)
+		}
+		if !board.Contains(pos) {
+			return fmt.Errorf("%s actor is out of the board: %v", c, pos)
+		}
+		if center.Contains(pos) {
+			return fmt.Errorf("%s actor is in the center: %v", c, pos)
+		}
+		if other, ok := occupied[pos]; ok {
+			return fmt.Errorf("%s actor is on %s actor: %v", c, other, pos)
+		}
+		occupied[pos] = c
+	}
+	if !board.Contains(p.Goal.Point) {
+		return fmt.Errorf("goal is out of the board: %v", p.Goal.Point)
+	}
+	if center.Contains(p.Goal.Point) {
+		return fmt.Errorf("goal is in the center: %v", p.Goal.Point)
+	}
+	if c, ok := occupied[p.Goal.Point]; ok {
+		return fmt.Errorf("goal is under %s actor: %v", c, p.Goal.Point)
+	}
+	return nil
+}
+
+// NewBoard creates a board of the puzzle.
+// The placement algorithms are used for new games started on the board.
+func (p *Puzzle) NewBoard(placement Placement) (*Board, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	m, err := NewMapdataFromSlice(p.Walls)
+	if err != nil {
+		return nil, err
+	}
+	b := newBoard(m, placement)
+	b.PlaceActors(p.Actors)
+	b.Goal = p.Goal
+	return b, nil
+}
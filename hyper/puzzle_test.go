@@ -0,0 +1,181 @@
+package hyper_test
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+func TestPuzzle_Validate(t *testing.T) {
+	walls := [][]int{
+		{0, 0, 0, 0},
+		{0, 3, 1, 2},
+		{0, 2, 0, 3},
+		{0, 1, 3, 0},
+	}
+	goal := hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}}
+
+	testcases := []struct {
+		Name    string
+		Puzzle  *hyper.Puzzle
+		IsValid bool
+	}{
+		{
+			"valid",
+			&hyper.Puzzle{
+				Walls:  walls,
+				Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+				Goal:   goal,
+			},
+			true,
+		},
+		{
+			"too small",
+			&hyper.Puzzle{
+				Walls:  [][]int{{0}},
+				Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+				Goal:   goal,
+			},
+			false,
+		},
+		{
+			"not rectangular",
+			&hyper.Puzzle{
+				Walls:  [][]int{{0, 0, 0, 0}, {0, 3, 1, 2}, {0, 2, 0}, {0, 1, 3, 0}},
+				Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+				Goal:   goal,
+			},
+			false,
+		},
+		{
+			"missing actor",
+			&hyper.Puzzle{
+				Walls:  walls,
+				Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}},
+				Goal:   goal,
+			},
+			false,
+		},
+		{
+			"actor out of the board",
+			&hyper.Puzzle{
+				Walls:  walls,
+				Actors: map[hyper.Color]hyper.Point{hyper.Red: {4, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+				Goal:   goal,
+			},
+			false,
+		},
+		{
+			"actor in the center",
+			&hyper.Puzzle{
+				Walls:  walls,
+				Actors: map[hyper.Color]hyper.Point{hyper.Red: {2, 2}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+				Goal:   goal,
+			},
+			false,
+		},
+		{
+			"actors overlapping",
+			&hyper.Puzzle{
+				Walls:  walls,
+				Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 3}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+				Goal:   goal,
+			},
+			false,
+		},
+		{
+			"goal in the center",
+			&hyper.Puzzle{
+				Walls:  walls,
+				Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+				Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 1}},
+			},
+			false,
+		},
+		{
+			"goal under an actor",
+			&hyper.Puzzle{
+				Walls:  walls,
+				Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+				Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{3, 0}},
+			},
+			false,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			err := testcase.Puzzle.Validate()
+			if testcase.IsValid && err != nil {
+				t.Errorf("expected to be valid, but got %v", err)
+			}
+			if !testcase.IsValid && err == nil {
+				t.Error("expected to be invalid")
+			}
+		})
+	}
+}
+
+func TestPuzzle_WriteAndRead(t *testing.T) {
+	expected := &hyper.Puzzle{
+		Walls: [][]int{
+			{0, 0, 0, 0},
+			{0, 3, 1, 2},
+			{0, 2, 0, 3},
+			{0, 1, 3, 0},
+		},
+		Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+		Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+	}
+	buf := &bytes.Buffer{}
+	if err := expected.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := hyper.ReadPuzzle(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.EqualFunc(expected.Walls, actual.Walls, slices.Equal) {
+		t.Errorf("walls: expected %v, actual %v", expected.Walls, actual.Walls)
+	}
+	if !maps.Equal(expected.Actors, actual.Actors) {
+		t.Errorf("actors: expected %v, actual %v", expected.Actors, actual.Actors)
+	}
+	if expected.Goal != actual.Goal {
+		t.Errorf("goal: expected %v, actual %v", expected.Goal, actual.Goal)
+	}
+}
+
+func TestPuzzle_NewBoard(t *testing.T) {
+	expected := &hyper.Puzzle{
+		Walls: [][]int{
+			{0, 0, 0, 0},
+			{0, 3, 1, 2},
+			{0, 2, 0, 3},
+			{0, 1, 3, 0},
+		},
+		Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+		Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+	}
+	b, err := expected.NewBoard(hyper.Placement{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.MoveActor(b.Actors[hyper.Blue], hyper.East)
+	b.MoveActor(b.Actors[hyper.Red], hyper.South)
+
+	// the puzzle of the board is the initial state regardless of moves
+	actual := hyper.NewPuzzle(b)
+	if !slices.EqualFunc(expected.Walls, actual.Walls, slices.Equal) {
+		t.Errorf("walls: expected %v, actual %v", expected.Walls, actual.Walls)
+	}
+	if !maps.Equal(expected.Actors, actual.Actors) {
+		t.Errorf("actors: expected %v, actual %v", expected.Actors, actual.Actors)
+	}
+	if expected.Goal != actual.Goal {
+		t.Errorf("goal: expected %v, actual %v", expected.Goal, actual.Goal)
+	}
+}
@@ -8,8 +8,18 @@ import (
 
 // Choice performs weighted random selection from candidates using the given weights.
 func Choice[T any](candidates []T, weights []int) T {
+	return choice(rand.Intn, candidates, weights)
+}
+
+// ChoiceBy performs weighted random selection like Choice, drawing from the random source.
+func ChoiceBy[T any](src *rand.Rand, candidates []T, weights []int) T {
+	return choice(src.Intn, candidates, weights)
+}
+
+// choice performs weighted random selection with intn, which returns a random number in [0, n).
+func choice[T any](intn func(n int) int, candidates []T, weights []int) T {
 	total := slicetools.Sum(weights)
-	r := rand.Intn(total)
+	r := intn(total)
 
 	for i, w := range weights {
 		r -= w
@@ -0,0 +1,96 @@
+package hyper
+
+import "slices"
+
+// others returns the actors other than the one of the color.
+func (b *Board) others(c Color) []*Actor {
+	actors := []*Actor{}
+	for _, actor := range b.Actors {
+		if actor.Color != c {
+			actors = append(actors, actor)
+		}
+	}
+	return actors
+}
+
+// Moves returns where the actor of the color would stop when moving from p in each direction it can move to.
+// The other actors stay put, and the actor itself does not block its way wherever it actually is.
+func (b *Board) Moves(c Color, p Point) map[Direction]Point {
+	return b.moves(p, b.others(c))
+}
+
+// moves returns where an actor would stop when moving from p in each direction, blocked by the given actors.
+func (b *Board) moves(p Point, blockers []*Actor) map[Direction]Point {
+	stops := map[Direction]Point{}
+	for _, d := range AllDirections {
+		if stop := b.nextStop(p, d, blockers); !stop.Equals(p) {
+			stops[d] = stop
+		}
+	}
+	return stops
+}
+
+// Reachable returns the minimum number of moves for the actor of the color to stop at each cell, up to maxMoves.
+// The current position of the actor is included with 0 moves, and cells not reachable within maxMoves are not included.
+func (b *Board) Reachable(c Color, maxMoves int) map[Point]int {
+	actor, ok := b.Actors[c]
+	if !ok {
+		return map[Point]int{}
+	}
+	blockers := b.others(c)
+
+	// breadth-first search
+	distances := map[Point]int{actor.Point: 0}
+	frontier := []Point{actor.Point}
+	for moves := 1; moves <= maxMoves && len(frontier) > 0; moves++ {
+		next := []Point{}
+		for _, p := range frontier {
+			for _, stop := range b.moves(p, blockers) {
+				if _, visited := distances[stop]; !visited {
+					distances[stop] = moves
+					next = append(next, stop)
+				}
+			}
+		}
+		frontier = next
+	}
+	return distances
+}
+
+// DistancesToGoal returns the minimum number of moves for the actor of the color to stop at the goal from each cell.
+// The other actors stay put. Cells occupied by them, the center box and cells from which the goal cannot be reached are not included.
+func (b *Board) DistancesToGoal(c Color) map[Point]int {
+	blockers := b.others(c)
+	center := b.Mapdata.Center()
+
+	// reverse the moves between cells
+	sources := map[Point][]Point{}
+	for y := range b.Mapdata.H {
+		for x := range b.Mapdata.W {
+			p := Point{x, y}
+			if center.Contains(p) || slices.ContainsFunc(blockers, func(actor *Actor) bool { return actor.Point.Equals(p) }) {
+				continue
+			}
+			for _, stop := range b.moves(p, blockers) {
+				sources[stop] = append(sources[stop], p)
+			}
+		}
+	}
+
+	// breadth-first search from the goal
+	distances := map[Point]int{b.Goal.Point: 0}
+	frontier := []Point{b.Goal.Point}
+	for moves := 1; len(frontier) > 0; moves++ {
+		next := []Point{}
+		for _, p := range frontier {
+			for _, source := range sources[p] {
+				if _, visited := distances[source]; !visited {
+					distances[source] = moves
+					next = append(next, source)
+				}
+			}
+		}
+		frontier = next
+	}
+	return distances
+}
@@ -0,0 +1,109 @@
+package hyper_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+func TestBoard_Reachable(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		MaxMoves int
+		Actors   map[hyper.Color]hyper.Point
+		Expected map[hyper.Point]int
+	}{
+		{
+			"no move",
+			0,
+			map[hyper.Color]hyper.Point{hyper.Red: {1, 1}},
+			map[hyper.Point]int{{1, 1}: 0},
+		},
+		{
+			"one move",
+			1,
+			map[hyper.Color]hyper.Point{hyper.Red: {1, 1}},
+			map[hyper.Point]int{
+				{1, 1}:  0,
+				{1, 0}:  1,
+				{0, 1}:  1,
+				{13, 1}: 1, // blocked by Green
+				{1, 13}: 1, // blocked by Blue
+			},
+		},
+		{
+			"unable to move",
+			2,
+			map[hyper.Color]hyper.Point{hyper.Red: {0, 5}, hyper.Green: {0, 4}, hyper.Blue: {0, 6}, hyper.Yellow: {1, 5}},
+			map[hyper.Point]int{{0, 5}: 0},
+		},
+		{
+			"blocked by others only",
+			2,
+			map[hyper.Color]hyper.Point{hyper.Red: {0, 5}, hyper.Green: {2, 5}, hyper.Blue: {0, 6}},
+			map[hyper.Point]int{
+				{0, 5}:  0,
+				{0, 0}:  1,
+				{1, 5}:  1,
+				{15, 0}: 2,
+				{1, 0}:  2,
+				{1, 15}: 2,
+				// the actor itself does not block the way back to {0, 5}
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			b, err := hyper.NewBoard(hyper.Size{16, 16}, hyper.Placement{
+				Actor: hyper.PlaceActorAt(defaultActorPlacement, testcase.Actors),
+				Goal:  hyper.PlaceGoalAt(hyper.Red, hyper.Point{3, 3}),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual := b.Reachable(hyper.Red, testcase.MaxMoves)
+			if !maps.Equal(testcase.Expected, actual) {
+				t.Errorf("unexpected value: Expected = %v, Actual = %v", testcase.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestBoard_DistancesToGoal(t *testing.T) {
+	b, err := hyper.NewBoard(hyper.Size{16, 16}, hyper.Placement{
+		Actor: hyper.PlaceActorAt(defaultActorPlacement, map[hyper.Color]hyper.Point{hyper.Green: {0, 5}}),
+		Goal:  hyper.PlaceGoalAt(hyper.Red, hyper.Point{1, 0}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	distances := b.DistancesToGoal(hyper.Red)
+
+	testcases := []struct {
+		Name      string
+		Point     hyper.Point
+		Expected  int
+		Reachable bool
+	}{
+		{"goal", hyper.Point{1, 0}, 0, true},
+		{"one move", hyper.Point{1, 5}, 1, true},
+		{"one move from the actor", hyper.Point{1, 1}, 1, true},
+		{"two moves", hyper.Point{5, 5}, 2, true}, // stop by Green and then move north
+		{"occupied by another actor", hyper.Point{1, 14}, 0, false},
+		{"center box", hyper.Point{7, 7}, 0, false},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			actual, ok := distances[testcase.Point]
+			if ok != testcase.Reachable {
+				t.Fatalf("unexpected reachability: Expected = %v, Actual = %v", testcase.Reachable, ok)
+			}
+			if actual != testcase.Expected {
+				t.Errorf("unexpected value: Expected = %d, Actual = %d", testcase.Expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package hyper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SavedMove is a move in the history tree of a SavedGame.
+type SavedMove struct {
+	Color      Color
+	Direction  Direction
+	Start, End Point
+	Active     int          `json:",omitempty"` // index of the child followed by Redo
+	Children   []*SavedMove `json:",omitempty"` // alternative moves following this one
+}
+
+// SavedGame is a game in progress which can be resumed later: the puzzle, the history tree and the current move.
+type SavedGame struct {
+	Puzzle *Puzzle
+	Active int          // index of the first move followed by Redo
+	Moves  []*SavedMove // alternative first moves
+	Steps  int          // number of moves taken on the active line to the current state
+	Daily  string       `json:",omitempty"` // date of the daily puzzle played in the game in DAILY_DATE_FORMAT, or empty
+	Pack   string       `json:",omitempty"` // title of the pack of the puzzle played in the game, or empty
+	Index  int          `json:",omitempty"` // index of the puzzle in the pack
+}
+
+// NewSavedGame returns the game in progress on the board.
+func NewSavedGame(b *Board) *SavedGame {
+	root := b.HistoryTree()
+	return &SavedGame{
+		Puzzle: NewPuzzle(b),
+		Active: root.active,
+		Moves:  saveMoves(root.Children),
+		Steps:  b.Steps(),
+	}
+}
+
+// saveMoves converts the nodes and their descendants into SavedMoves.
+func saveMoves(nodes []*Node) []*SavedMove {
+	moves := make([]*SavedMove, len(nodes))
+	for i, n := range nodes {
+		moves[i] = &SavedMove{
+			Color:     n.Color,
+			Direction: n.Direction,
+			Start:     n.Start,
+			End:       n.End,
+			Active:    n.active,
+			Children:  saveMoves(n.Children),
+		}
+	}
+	return moves
+}
+
+// ReadSavedGame decodes a saved game from JSON.
+func ReadSavedGame(r io.Reader) (*SavedGame, error) {
+	s := &SavedGame{}
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, err
+	}
+	if s.Puzzle == nil {
+		return nil, fmt.Errorf("puzzle is missing")
+	}
+	return s, nil
+}
+
+// Write encodes the saved game as JSON.
+func (s *SavedGame) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// NewBoard creates a board of the saved game, restoring the history tree and the current move.
+// The placement algorithms are used for new games started on the board.
+func (s *SavedGame) NewBoard(placement Placement) (*Board, error) {
+	b, err := s.Puzzle.NewBoard(placement)
+	if err != nil {
+		return nil, err
+	}
+	root := b.history.Root()
+	if err := restoreMoves(root, s.Active, s.Moves); err != nil {
+		return nil, err
+	}
+	for range s.Steps {
+		r := b.history.Redo()
+		if r == nil {
+			return nil, fmt.Errorf("unable to take %d steps", s.Steps)
+		}
+		actor := b.Actors[r.Color]
+		if !actor.Point.Equals(r.Start) {
+			return nil, fmt.Errorf("%s actor is not at the start of the move: %v", r.Color, r.Start)
+		}
+		actor.MoveTo(r.End)
+	}
+	b.updateGoaled(b.history.Current())
+	return b, nil
+}
+
+// restoreMoves adds the moves and their descendants to the node as its children.
+func restoreMoves(parent *Node, active int, moves []*SavedMove) error {
+	if len(moves) > 0 && (active < 0 || len(moves) <= active) {
+		return fmt.Errorf("invalid index of the active move: %d", active)
+	}
+	parent.active = active
+	for _, m := range moves {
+		child := &Node{
+			Record: &Record{m.Color, m.Direction, m.Start, m.End},
+			Steps:  parent.Steps + 1,
+			Parent: parent,
+		}
+		parent.Children = append(parent.Children, child)
+		if err := restoreMoves(child, m.Active, m.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package hyper_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+func TestSavedGame(t *testing.T) {
+	puzzle := &hyper.Puzzle{
+		Walls: [][]int{
+			{0, 0, 0, 0},
+			{0, 3, 1, 2},
+			{0, 2, 0, 3},
+			{0, 1, 3, 0},
+		},
+		Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+		Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+	}
+
+	testcases := []struct {
+		Name    string
+		Operate func(b *hyper.Board)
+	}{
+		{
+			"no moves",
+			func(b *hyper.Board) {},
+		},
+		{
+			"moves",
+			func(b *hyper.Board) {
+				b.MoveActor(b.Actors[hyper.Blue], hyper.East)
+				b.MoveActor(b.Actors[hyper.Red], hyper.South)
+			},
+		},
+		{
+			"undone moves",
+			func(b *hyper.Board) {
+				b.MoveActor(b.Actors[hyper.Blue], hyper.East)
+				b.MoveActor(b.Actors[hyper.Red], hyper.South)
+				b.Undo()
+			},
+		},
+		{
+			"branches",
+			func(b *hyper.Board) {
+				b.MoveActor(b.Actors[hyper.Blue], hyper.East)
+				b.MoveActor(b.Actors[hyper.Red], hyper.South)
+				b.Undo()
+				b.MoveActor(b.Actors[hyper.Red], hyper.East)
+				b.Reset()
+				b.MoveActor(b.Actors[hyper.Green], hyper.South)
+				b.JumpTo(b.HistoryTree().Children[0].Children[1])
+				b.Undo()
+			},
+		},
+		{
+			"goal reached",
+			func(b *hyper.Board) {
+				b.Goal = hyper.Goal{Color: hyper.Red, Point: hyper.Point{0, 2}}
+				b.MoveActor(b.Actors[hyper.Red], hyper.South)
+				if !b.Goaled {
+					t.Fatal("expected the goal to be reached")
+				}
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			expected, err := puzzle.NewBoard(hyper.Placement{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			testcase.Operate(expected)
+
+			buf := &bytes.Buffer{}
+			if err := hyper.NewSavedGame(expected).Write(buf); err != nil {
+				t.Fatal(err)
+			}
+			saved, err := hyper.ReadSavedGame(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := saved.NewBoard(hyper.Placement{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expected.Steps() != actual.Steps() {
+				t.Errorf("steps: expected %d, actual %d", expected.Steps(), actual.Steps())
+			}
+			if expected.Goaled != actual.Goaled {
+				t.Errorf("goaled: expected %v, actual %v", expected.Goaled, actual.Goaled)
+			}
+			for c, actor := range expected.Actors {
+				if !actor.Point.Equals(actual.Actors[c].Point) {
+					t.Errorf("%s actor: expected %v, actual %v", c, actor.Point, actual.Actors[c].Point)
+				}
+			}
+			assertSameTree(t, expected.HistoryTree(), actual.HistoryTree())
+			if e, a := expected.CurrentMove().Path(), actual.CurrentMove().Path(); len(e) != len(a) {
+				t.Errorf("current move: expected %d steps, actual %d steps", len(e), len(a))
+			}
+			if e, a := expected.History(), actual.History(); len(e) != len(a) {
+				t.Errorf("active line: expected %d moves, actual %d moves", len(e), len(a))
+			}
+		})
+	}
+}
+
+// assertSameTree fails the test if the history trees have different moves.
+func assertSameTree(t *testing.T, expected, actual *hyper.Node) {
+	t.Helper()
+	if (expected.Record == nil) != (actual.Record == nil) || (expected.Record != nil && !expected.Record.Equals(actual.Record)) {
+		t.Errorf("move: expected %v, actual %v", expected.Record, actual.Record)
+		return
+	}
+	if len(expected.Children) != len(actual.Children) {
+		t.Errorf("children of %v: expected %d, actual %d", expected.Record, len(expected.Children), len(actual.Children))
+		return
+	}
+	if expected.Active() != nil && expected.Active().Record != nil && !expected.Active().Record.Equals(actual.Active().Record) {
+		t.Errorf("active child of %v: expected %v, actual %v", expected.Record, expected.Active().Record, actual.Active().Record)
+	}
+	for i := range expected.Children {
+		assertSameTree(t, expected.Children[i], actual.Children[i])
+	}
+}
+
+func TestSavedGame_Invalid(t *testing.T) {
+	puzzle := &hyper.Puzzle{
+		Walls: [][]int{
+			{0, 0, 0, 0},
+			{0, 3, 1, 2},
+			{0, 2, 0, 3},
+			{0, 1, 3, 0},
+		},
+		Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+		Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+	}
+
+	testcases := []struct {
+		Name  string
+		Saved *hyper.SavedGame
+	}{
+		{
+			"too many steps",
+			&hyper.SavedGame{
+				Puzzle: puzzle,
+				Moves:  []*hyper.SavedMove{{Color: hyper.Red, Direction: hyper.South, Start: hyper.Point{0, 0}, End: hyper.Point{0, 2}}},
+				Steps:  2,
+			},
+		},
+		{
+			"invalid active move",
+			&hyper.SavedGame{
+				Puzzle: puzzle,
+				Active: 1,
+				Moves:  []*hyper.SavedMove{{Color: hyper.Red, Direction: hyper.South, Start: hyper.Point{0, 0}, End: hyper.Point{0, 2}}},
+				Steps:  1,
+			},
+		},
+		{
+			"move from elsewhere",
+			&hyper.SavedGame{
+				Puzzle: puzzle,
+				Moves:  []*hyper.SavedMove{{Color: hyper.Red, Direction: hyper.South, Start: hyper.Point{0, 1}, End: hyper.Point{0, 2}}},
+				Steps:  1,
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			if _, err := testcase.Saved.NewBoard(hyper.Placement{}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package hyper
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// ColorStats counts games by the color of the goal.
+type ColorStats struct {
+	Played int
+	Solved int
+}
+
+// Stats is the record of games played by the player.
+// A game is played once an actor moves, and solved when the goal is reached for the first time.
+type Stats struct {
+	Played     int
+	Solved     int
+	TotalSteps int           // steps of all solved games
+	BestSteps  int           // fewest steps to solve a game, or 0 if none is solved
+	Undos      int           // moves undone
+	Resets     int           // boards reset
+	TotalTime  time.Duration // time to solve all solved games
+	BestTime   time.Duration // shortest time to solve a game, or 0 if none is solved
+	Streak     int           // games solved in a row, up to now
+	BestStreak int           // games solved in a row, at most
+	Colors     map[Color]*ColorStats
+}
+
+// NewStats returns the empty stats.
+func NewStats() *Stats {
+	return &Stats{Colors: map[Color]*ColorStats{}}
+}
+
+// ReadStats decodes stats from JSON.
+func ReadStats(r io.Reader) (*Stats, error) {
+	s := NewStats()
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, err
+	}
+	if s.Colors == nil {
+		s.Colors = map[Color]*ColorStats{}
+	}
+	return s, nil
+}
+
+// Write encodes the stats as JSON.
+func (s *Stats) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// AverageSteps returns the average steps to solve a game, or 0 if none is solved.
+func (s *Stats) AverageSteps() float64 {
+	if s.Solved == 0 {
+		return 0
+	}
+	return float64(s.TotalSteps) / float64(s.Solved)
+}
+
+// AverageTime returns the average time to solve a game, or 0 if none is solved.
+func (s *Stats) AverageTime() time.Duration {
+	if s.Solved == 0 {
+		return 0
+	}
+	return s.TotalTime / time.Duration(s.Solved)
+}
+
+// SuccessRate returns the ratio of solved games to played games whose goal is of the color, or 0 if none is played.
+func (s *Stats) SuccessRate(c Color) float64 {
+	cs, ok := s.Colors[c]
+	if !ok || cs.Played == 0 {
+		return 0
+	}
+	return float64(cs.Solved) / float64(cs.Played)
+}
+
+// color returns the counts of games whose goal is of the color.
+func (s *Stats) color(c Color) *ColorStats {
+	cs, ok := s.Colors[c]
+	if !ok {
+		cs = &ColorStats{}
+		s.Colors[c] = cs
+	}
+	return cs
+}
+
+// StatsTracker records games on the followed board into Stats.
+type StatsTracker struct {
+	*Stats
+	now         func() time.Time
+	board       *Board
+	unsubscribe func()
+	started     time.Time // when the current game started
+	played      bool      // whether the current game is counted as played
+	solved      bool      // whether the current game is counted as solved
+	changed     bool
+}
+
+// NewStatsTracker creates a StatsTracker recording into the stats, measuring time by the clock such as time.Now.
+func NewStatsTracker(s *Stats, now func() time.Time) *StatsTracker {
+	return &StatsTracker{Stats: s, now: now}
+}
+
+// Follow starts recording games on the board, if it is not followed yet.
+// The game on the previous board is abandoned, which breaks the streak if it is played and not solved.
+// Moves already made on the board, such as those of a resumed game, are not counted again.
+func (t *StatsTracker) Follow(b *Board) {
+	if b == t.board {
+		return
+	}
+	t.abandon()
+	if t.unsubscribe != nil {
+		t.unsubscribe()
+	}
+	t.board = b
+	t.unsubscribe = b.Subscribe(t.handle)
+	t.started = t.now()
+	t.played = len(b.HistoryTree().Children) > 0
+	t.solved = b.Goaled
+}
+
+// Changed reports whether the stats are changed since the last call.
+func (t *StatsTracker) Changed() bool {
+	changed := t.changed
+	t.changed = false
+	return changed
+}
+
+// abandon ends the current game, breaking the streak if it is played and not solved.
+func (t *StatsTracker) abandon() {
+	if t.played && !t.solved && t.Streak > 0 {
+		t.Streak = 0
+		t.changed = true
+	}
+}
+
+// handle records the event of the followed board.
+func (t *StatsTracker) handle(e *Event) {
+	switch e.Kind {
+	case MovedEvent:
+		if !t.played {
+			t.played = true
+			t.Played++
+			t.color(t.board.Goal.Color).Played++
+			t.changed = true
+		}
+	case GoalReachedEvent:
+		if !t.solved {
+			t.solve()
+		}
+	case UndoneEvent:
+		t.Undos++
+		t.changed = true
+	case ResetEvent:
+		t.Resets++
+		t.changed = true
+	case NewGameEvent:
+		t.abandon()
+		t.started = t.now()
+		t.played = false
+		t.solved = false
+	}
+}
+
+// solve records the current game as solved in the steps taken so far.
+func (t *StatsTracker) solve() {
+	t.solved = true
+	t.Solved++
+	t.color(t.board.Goal.Color).Solved++
+
+	steps := t.board.Steps()
+	t.TotalSteps += steps
+	if t.BestSteps == 0 || steps < t.BestSteps {
+		t.BestSteps = steps
+	}
+	elapsed := t.now().Sub(t.started)
+	t.TotalTime += elapsed
+	if t.BestTime == 0 || elapsed < t.BestTime {
+		t.BestTime = elapsed
+	}
+	t.Streak++
+	t.BestStreak = max(t.BestStreak, t.Streak)
+	t.changed = true
+}
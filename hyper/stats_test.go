@@ -0,0 +1,196 @@
+package hyper_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+func TestStatsTracker(t *testing.T) {
+	// new games are placed near walls, while the first goal is reached by Red moving south
+	newBoard := func(t *testing.T) *hyper.Board {
+		b, err := hyper.NewBoard(hyper.Size{8, 8}, hyper.Placement{
+			Actor: hyper.PlaceActorAt(map[hyper.Color]hyper.Point{hyper.Red: {1, 0}, hyper.Green: {7, 0}, hyper.Blue: {0, 5}, hyper.Yellow: {6, 6}, hyper.Black: {0, 2}}),
+			Goal:  hyper.PlaceGoalNearByWalls,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Goal = hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 7}}
+		return b
+	}
+
+	testcases := []struct {
+		Name     string
+		Operate  func(t *testing.T, b *hyper.Board, tick func(d time.Duration))
+		Expected hyper.Stats
+	}{
+		{
+			"no moves",
+			func(t *testing.T, b *hyper.Board, tick func(d time.Duration)) {},
+			hyper.Stats{Colors: map[hyper.Color]*hyper.ColorStats{}},
+		},
+		{
+			"played",
+			func(t *testing.T, b *hyper.Board, tick func(d time.Duration)) {
+				b.MoveActor(b.Actors[hyper.Blue], hyper.East)
+				b.MoveActor(b.Actors[hyper.Green], hyper.West)
+			},
+			hyper.Stats{
+				Played: 1,
+				Colors: map[hyper.Color]*hyper.ColorStats{hyper.Red: {Played: 1}},
+			},
+		},
+		{
+			"solved",
+			func(t *testing.T, b *hyper.Board, tick func(d time.Duration)) {
+				b.MoveActor(b.Actors[hyper.Blue], hyper.East)
+				tick(5 * time.Second)
+				b.MoveActor(b.Actors[hyper.Red], hyper.South)
+			},
+			hyper.Stats{
+				Played:     1,
+				Solved:     1,
+				TotalSteps: 2,
+				BestSteps:  2,
+				TotalTime:  5 * time.Second,
+				BestTime:   5 * time.Second,
+				Streak:     1,
+				BestStreak: 1,
+				Colors:     map[hyper.Color]*hyper.ColorStats{hyper.Red: {Played: 1, Solved: 1}},
+			},
+		},
+		{
+			"undo, reset and redo to the goal are not solved again",
+			func(t *testing.T, b *hyper.Board, tick func(d time.Duration)) {
+				b.MoveActor(b.Actors[hyper.Red], hyper.South)
+				b.Undo()
+				b.Redo()
+				b.Reset()
+				b.Redo()
+			},
+			hyper.Stats{
+				Played:     1,
+				Solved:     1,
+				TotalSteps: 1,
+				BestSteps:  1,
+				Undos:      1,
+				Resets:     1,
+				Streak:     1,
+				BestStreak: 1,
+				Colors:     map[hyper.Color]*hyper.ColorStats{hyper.Red: {Played: 1, Solved: 1}},
+			},
+		},
+		{
+			"abandoned game breaks the streak",
+			func(t *testing.T, b *hyper.Board, tick func(d time.Duration)) {
+				b.MoveActor(b.Actors[hyper.Red], hyper.South)
+				if err := b.NewGame(); err != nil {
+					t.Fatal(err)
+				}
+				b.Goal = hyper.Goal{Color: hyper.Green, Point: hyper.Point{5, 1}}
+				b.MoveActor(b.Actors[hyper.Blue], hyper.East)
+				if err := b.NewGame(); err != nil {
+					t.Fatal(err)
+				}
+			},
+			hyper.Stats{
+				Played:     2,
+				Solved:     1,
+				TotalSteps: 1,
+				BestSteps:  1,
+				Streak:     0,
+				BestStreak: 1,
+				Colors: map[hyper.Color]*hyper.ColorStats{
+					hyper.Red:   {Played: 1, Solved: 1},
+					hyper.Green: {Played: 1},
+				},
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			tick := func(d time.Duration) { now = now.Add(d) }
+			b := newBoard(t)
+			tracker := hyper.NewStatsTracker(hyper.NewStats(), func() time.Time { return now })
+			tracker.Follow(b)
+			testcase.Operate(t, b, tick)
+
+			actual := *tracker.Stats
+			if !reflect.DeepEqual(testcase.Expected, actual) {
+				t.Errorf("expected %+v, but got %+v", testcase.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestStatsTracker_Follow(t *testing.T) {
+	// new games are placed near walls, while the first goal is reached by Red moving south
+	newBoard := func(t *testing.T) *hyper.Board {
+		b, err := hyper.NewBoard(hyper.Size{8, 8}, hyper.Placement{
+			Actor: hyper.PlaceActorAt(map[hyper.Color]hyper.Point{hyper.Red: {1, 0}, hyper.Green: {7, 0}, hyper.Blue: {0, 5}, hyper.Yellow: {6, 6}, hyper.Black: {0, 2}}),
+			Goal:  hyper.PlaceGoalNearByWalls,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Goal = hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 7}}
+		return b
+	}
+
+	tracker := hyper.NewStatsTracker(hyper.NewStats(), time.Now)
+	first := newBoard(t)
+	tracker.Follow(first)
+	first.MoveActor(first.Actors[hyper.Red], hyper.South)
+	if !tracker.Changed() {
+		t.Error("expected the stats to be changed")
+	}
+	if tracker.Changed() {
+		t.Error("expected the change to be cleared")
+	}
+
+	// moves of the resumed board are not counted again
+	resumed := newBoard(t)
+	resumed.MoveActor(resumed.Actors[hyper.Blue], hyper.East)
+	tracker.Follow(resumed)
+	if tracker.Played != 1 || tracker.Streak != 1 {
+		t.Errorf("expected 1 game played in the streak of 1, but got %d played in the streak of %d", tracker.Played, tracker.Streak)
+	}
+
+	// the resumed game is abandoned
+	tracker.Follow(newBoard(t))
+	if tracker.Streak != 0 {
+		t.Errorf("expected the streak to be broken, but got %d", tracker.Streak)
+	}
+}
+
+func TestStats_Write(t *testing.T) {
+	expected := hyper.NewStats()
+	expected.Played = 3
+	expected.Solved = 2
+	expected.TotalTime = 90 * time.Second
+	expected.Colors[hyper.Black] = &hyper.ColorStats{Played: 3, Solved: 2}
+
+	buf := &bytes.Buffer{}
+	if err := expected.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := hyper.ReadStats(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v, but got %+v", expected, actual)
+	}
+	if rate := actual.SuccessRate(hyper.Black); rate != 2.0/3.0 {
+		t.Errorf("expected the success rate of 2/3, but got %f", rate)
+	}
+	if avg := actual.AverageTime(); avg != 45*time.Second {
+		t.Errorf("expected the average time of 45s, but got %v", avg)
+	}
+}
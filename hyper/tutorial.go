@@ -0,0 +1,74 @@
+package hyper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// TutorialStep is a step of a Tutorial, which explains something and allows only one move.
+type TutorialStep struct {
+	Puzzle    *Puzzle `json:",omitempty"` // board to start the step on, or nil to continue on the board of the previous step
+	Text      string
+	Color     Color     // actor to move
+	Direction Direction // direction to move the actor to
+	Expected  Point     // where the actor is expected to stop
+}
+
+// Allows reports whether the step allows the actor of the color to move in the direction.
+func (s *TutorialStep) Allows(c Color, d Direction) bool {
+	return c == s.Color && d == s.Direction
+}
+
+// Reached reports whether the actor of the step stops at the expected position on the board.
+func (s *TutorialStep) Reached(b *Board) bool {
+	actor, ok := b.Actors[s.Color]
+	return ok && actor.Point.Equals(s.Expected)
+}
+
+// Tutorial is a script of steps, which teaches the rules by letting players make the moves one by one.
+type Tutorial struct {
+	Steps []*TutorialStep
+	Done  string // text shown after the last step
+}
+
+// ReadTutorial decodes a tutorial from JSON and validates it.
+func ReadTutorial(r io.Reader) (*Tutorial, error) {
+	t := &Tutorial{}
+	if err := json.NewDecoder(r).Decode(t); err != nil {
+		return nil, err
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Validate plays the script, and returns an error if the puzzle of any step is invalid
+// or the move of any step does not stop the actor at the expected position.
+func (t *Tutorial) Validate() error {
+	if len(t.Steps) == 0 {
+		return errors.New("no steps")
+	}
+	var b *Board
+	for i, s := range t.Steps {
+		if s.Puzzle != nil {
+			var err error
+			if b, err = s.Puzzle.NewBoard(Placement{}); err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+		}
+		if b == nil {
+			return fmt.Errorf("step %d: puzzle is missing", i+1)
+		}
+		actor, ok := b.Actors[s.Color]
+		if !ok {
+			return fmt.Errorf("step %d: unknown color %d", i+1, s.Color)
+		}
+		if pos, _ := b.MoveActor(actor, s.Direction); !pos.Equals(s.Expected) {
+			return fmt.Errorf("step %d: %s actor stops at %v instead of %v", i+1, s.Color, pos, s.Expected)
+		}
+	}
+	return nil
+}
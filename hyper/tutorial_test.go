@@ -0,0 +1,181 @@
+package hyper_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+func TestTutorial_Validate(t *testing.T) {
+	puzzle := &hyper.Puzzle{
+		Walls: [][]int{
+			{0, 0, 0, 0},
+			{0, 3, 1, 2},
+			{0, 2, 0, 3},
+			{0, 1, 3, 0},
+		},
+		Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+		Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+	}
+	invalid := &hyper.Puzzle{Walls: puzzle.Walls, Actors: map[hyper.Color]hyper.Point{hyper.Blue: {0, 3}}, Goal: puzzle.Goal}
+
+	testcases := []struct {
+		Name  string
+		Steps []*hyper.TutorialStep
+		Valid bool
+	}{
+		{
+			"valid",
+			[]*hyper.TutorialStep{
+				{Puzzle: puzzle, Color: hyper.Blue, Direction: hyper.East, Expected: hyper.Point{1, 3}},
+				{Color: hyper.Red, Direction: hyper.South, Expected: hyper.Point{0, 3}},
+			},
+			true,
+		},
+		{
+			"no steps",
+			nil,
+			false,
+		},
+		{
+			"no puzzle at first",
+			[]*hyper.TutorialStep{
+				{Color: hyper.Blue, Direction: hyper.East, Expected: hyper.Point{1, 3}},
+			},
+			false,
+		},
+		{
+			"invalid puzzle",
+			[]*hyper.TutorialStep{
+				{Puzzle: invalid, Color: hyper.Blue, Direction: hyper.East, Expected: hyper.Point{1, 3}},
+			},
+			false,
+		},
+		{
+			"unexpected stop",
+			[]*hyper.TutorialStep{
+				{Puzzle: puzzle, Color: hyper.Blue, Direction: hyper.East, Expected: hyper.Point{1, 3}},
+				{Color: hyper.Red, Direction: hyper.South, Expected: hyper.Point{0, 2}},
+			},
+			false,
+		},
+		{
+			"unable to move",
+			[]*hyper.TutorialStep{
+				{Puzzle: puzzle, Color: hyper.Blue, Direction: hyper.East, Expected: hyper.Point{1, 3}},
+				{Color: hyper.Red, Direction: hyper.West, Expected: hyper.Point{0, 3}},
+			},
+			false,
+		},
+		{
+			"puzzle of each step",
+			[]*hyper.TutorialStep{
+				{Puzzle: puzzle, Color: hyper.Blue, Direction: hyper.East, Expected: hyper.Point{1, 3}},
+				{Color: hyper.Red, Direction: hyper.South, Expected: hyper.Point{0, 3}},
+				// Red moves from its initial position again on the new board
+				{Puzzle: puzzle, Color: hyper.Red, Direction: hyper.South, Expected: hyper.Point{0, 2}},
+			},
+			true,
+		},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			tu := &hyper.Tutorial{Steps: testcase.Steps, Done: "Done"}
+			if err := tu.Validate(); (err == nil) != testcase.Valid {
+				t.Errorf("expected valid = %v, but got error %v", testcase.Valid, err)
+			}
+		})
+	}
+}
+
+func TestReadTutorial(t *testing.T) {
+	puzzle := &hyper.Puzzle{
+		Walls: [][]int{
+			{0, 0, 0, 0},
+			{0, 3, 1, 2},
+			{0, 2, 0, 3},
+			{0, 1, 3, 0},
+		},
+		Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+		Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+	}
+	expected := &hyper.Tutorial{
+		Steps: []*hyper.TutorialStep{
+			{Puzzle: puzzle, Text: "Blue", Color: hyper.Blue, Direction: hyper.East, Expected: hyper.Point{1, 3}},
+			{Text: "Red", Color: hyper.Red, Direction: hyper.South, Expected: hyper.Point{0, 3}},
+		},
+		Done: "Done",
+	}
+	data, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := hyper.ReadTutorial(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual.Steps) != 2 || actual.Steps[1].Puzzle != nil || actual.Done != expected.Done {
+		t.Fatalf("unexpected tutorial: %+v", actual)
+	}
+	for i, s := range actual.Steps {
+		e := expected.Steps[i]
+		if s.Text != e.Text || s.Color != e.Color || s.Direction != e.Direction || !s.Expected.Equals(e.Expected) {
+			t.Errorf("step %d: expected %+v, but got %+v", i+1, e, s)
+		}
+	}
+}
+
+func TestTutorialStep(t *testing.T) {
+	puzzle := &hyper.Puzzle{
+		Walls: [][]int{
+			{0, 0, 0, 0},
+			{0, 3, 1, 2},
+			{0, 2, 0, 3},
+			{0, 1, 3, 0},
+		},
+		Actors: map[hyper.Color]hyper.Point{hyper.Red: {0, 0}, hyper.Green: {3, 0}, hyper.Blue: {0, 3}, hyper.Yellow: {3, 3}, hyper.Black: {2, 0}},
+		Goal:   hyper.Goal{Color: hyper.Red, Point: hyper.Point{1, 3}},
+	}
+	b, err := puzzle.NewBoard(hyper.Placement{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := &hyper.TutorialStep{Color: hyper.Red, Direction: hyper.South, Expected: hyper.Point{0, 2}}
+	if !step.Allows(hyper.Red, hyper.South) || step.Allows(hyper.Red, hyper.North) || step.Allows(hyper.Blue, hyper.South) {
+		t.Error("expected only Red moving south to be allowed")
+	}
+	if step.Reached(b) {
+		t.Error("expected the step not to be reached before the move")
+	}
+	b.MoveActor(b.Actors[hyper.Red], hyper.South)
+	if !step.Reached(b) {
+		t.Errorf("expected the step to be reached: %+v", b.Actors[hyper.Red])
+	}
+}
+
+func TestDirection_UnmarshalText(t *testing.T) {
+	for _, d := range hyper.AllDirections {
+		t.Run(d.String(), func(t *testing.T) {
+			data, err := json.Marshal(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var actual hyper.Direction
+			if err := json.Unmarshal(data, &actual); err != nil {
+				t.Fatal(err)
+			}
+			if actual != d {
+				t.Errorf("unexpected value: data = %s, actual = %v", data, actual)
+			}
+		})
+	}
+
+	t.Run("unknown direction", func(t *testing.T) {
+		var d hyper.Direction
+		if err := d.UnmarshalText([]byte("Up")); err == nil {
+			t.Errorf("no error: %+v", d)
+		}
+	})
+}
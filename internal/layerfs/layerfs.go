@@ -0,0 +1,106 @@
+// Package layerfs provides a file system which stacks other file systems as layers.
+// Files in upper layers hide files of the same name in lower layers,
+// so that defaults can be overridden by dropping in files.
+package layerfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// FS is a read-only file system which looks up files in its layers in order.
+// The first layer is the uppermost one.
+type FS []fs.FS
+
+// New creates and returns FS consisting of the given layers, the uppermost first.
+func New(layers ...fs.FS) FS {
+	return FS(layers)
+}
+
+// Open opens the named file in the uppermost layer which has it.
+func (l FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	for _, layer := range l {
+		f, err := layer.Open(name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		info, err := f.Stat()
+		if err != nil || !info.IsDir() {
+			return f, err
+		}
+		// directories list the entries of all layers
+		entries, err := l.ReadDir(name)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &dir{File: f, entries: entries}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir returns entries of the named directory merged across all layers, sorted by name.
+// An entry in an upper layer hides entries of the same name in lower layers.
+func (l FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	entries := []fs.DirEntry{}
+	found := false
+	for _, layer := range l {
+		es, err := fs.ReadDir(layer, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		found = true
+		for _, e := range es {
+			hidden := slices.ContainsFunc(entries, func(other fs.DirEntry) bool {
+				return other.Name() == e.Name()
+			})
+			if !hidden {
+				entries = append(entries, e)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+// dir is a directory whose entries are merged across layers.
+type dir struct {
+	fs.File
+	entries []fs.DirEntry
+	offset  int
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	d.offset += n
+	return rest[:n], nil
+}
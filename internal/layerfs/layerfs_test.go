@@ -0,0 +1,80 @@
+package layerfs_test
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fj68/hyper-tux-go/internal/layerfs"
+)
+
+func TestFS_Open(t *testing.T) {
+	upper := fstest.MapFS{
+		"themes/classic.json": {Data: []byte("upper")},
+	}
+	lower := fstest.MapFS{
+		"themes/classic.json": {Data: []byte("lower")},
+		"themes/night.json":   {Data: []byte("lower")},
+	}
+	l := layerfs.New(upper, lower)
+
+	testcases := []struct {
+		Name     string
+		Path     string
+		Expected string
+	}{
+		{"overridden", "themes/classic.json", "upper"},
+		{"not overridden", "themes/night.json", "lower"},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			data, err := fs.ReadFile(l, testcase.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != testcase.Expected {
+				t.Errorf("unexpected value: Expected = %s, Actual = %s", testcase.Expected, data)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		if _, err := l.Open("themes/missing.json"); err == nil {
+			t.Errorf("no error")
+		}
+	})
+}
+
+func TestFS_ReadDir(t *testing.T) {
+	upper := fstest.MapFS{
+		"themes/night.json": {Data: []byte("upper")},
+	}
+	lower := fstest.MapFS{
+		"themes/classic.json": {Data: []byte("lower")},
+		"themes/night.json":   {Data: []byte("lower")},
+	}
+	l := layerfs.New(upper, lower)
+
+	entries, err := fs.ReadDir(l, "themes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if len(names) != 2 || names[0] != "classic.json" || names[1] != "night.json" {
+		t.Errorf("unexpected entries: %+v", names)
+	}
+}
+
+func TestFS(t *testing.T) {
+	l := layerfs.New(
+		fstest.MapFS{"a/b.txt": {Data: []byte("upper")}},
+		fstest.MapFS{"a/c.txt": {Data: []byte("lower")}},
+	)
+	if err := fstest.TestFS(l, "a/b.txt", "a/c.txt"); err != nil {
+		t.Error(err)
+	}
+}
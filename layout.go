@@ -0,0 +1,117 @@
+package main
+
+import (
+	"image"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+// CONTROLS_PADDING is the space around and between buttons in the controls panel in logical pixels.
+const CONTROLS_PADDING = 4 // px
+
+// PANEL_SIZE is the height of the panels of history, or their width in landscape, in logical pixels.
+const PANEL_SIZE = 160 // px
+
+// BRANCHES_SIZE is the height of the branch panel above the history list in landscape in logical pixels.
+const BRANCHES_SIZE = 96 // px
+
+// Layout describes where the stage, the controls and the panels are placed on the screen.
+// All values are in device pixels.
+type Layout struct {
+	Width, Height int
+	Scale         float64 // device scale factor
+	CellSize      float32
+	Stage         image.Rectangle
+	Controls      image.Rectangle
+	Columns       int             // of the grid of buttons in the controls
+	Branches      image.Rectangle // panel showing the history tree
+	History       image.Rectangle // list of moves
+	Landscape     bool            // whether the controls and the panels are placed on the right side of the stage
+}
+
+// NewLayout computes the layout for the screen of the given size, the board of the given size and the number of buttons in the controls.
+// The cell size is chosen so that the whole board, the controls and the panels fit in the screen.
+func NewLayout(width, height int, scale float64, board hyper.Size, buttons int) *Layout {
+	l := &Layout{
+		Width:     width,
+		Height:    height,
+		Scale:     scale,
+		Landscape: width > height,
+	}
+	columns, rows := controlsGrid(width, height, scale, buttons, l.Landscape)
+	l.Columns = columns
+	controlsSize := int(float64(rows*(BUTTON_HEIGHT+CONTROLS_PADDING)+CONTROLS_PADDING) * scale)
+	if l.Landscape {
+		controlsSize = int(float64(columns*(BUTTON_WIDTH+CONTROLS_PADDING)+CONTROLS_PADDING) * scale)
+	}
+	panelSize := int(PANEL_SIZE * scale)
+
+	available := image.Pt(width, height-controlsSize-panelSize)
+	if l.Landscape {
+		available = image.Pt(width-controlsSize-panelSize, height)
+	}
+	l.CellSize = float32(max(1, min(available.X/max(1, board.W), available.Y/max(1, board.H))))
+
+	stageSize := image.Pt(int(l.CellSize)*board.W, int(l.CellSize)*board.H)
+	if l.Landscape {
+		origin := image.Pt((available.X-stageSize.X)/2, (height-stageSize.Y)/2)
+		l.Stage = image.Rectangle{origin, origin.Add(stageSize)}
+		l.Controls = image.Rect(l.Stage.Max.X, 0, l.Stage.Max.X+controlsSize, height)
+		// branches above the list of moves
+		branchesSize := min(int(BRANCHES_SIZE*scale), height/2)
+		l.Branches = image.Rect(l.Controls.Max.X, 0, l.Controls.Max.X+panelSize, branchesSize)
+		l.History = image.Rect(l.Controls.Max.X, branchesSize, l.Controls.Max.X+panelSize, height)
+	} else {
+		origin := image.Pt((width-stageSize.X)/2, (available.Y-stageSize.Y)/2)
+		l.Stage = image.Rectangle{origin, origin.Add(stageSize)}
+		l.Controls = image.Rect(0, l.Stage.Max.Y, width, l.Stage.Max.Y+controlsSize)
+		// the list of moves on the left of branches
+		l.History = image.Rect(0, l.Controls.Max.Y, width/2, l.Controls.Max.Y+panelSize)
+		l.Branches = image.Rect(width/2, l.Controls.Max.Y, width, l.Controls.Max.Y+panelSize)
+	}
+
+	return l
+}
+
+// controlsGrid returns the numbers of columns and rows of the grid of buttons in the controls,
+// which has as many rows as fit in the height of the screen in landscape, or as many columns as fit in its width in portrait.
+func controlsGrid(width, height int, scale float64, buttons int, landscape bool) (columns, rows int) {
+	buttons = max(1, buttons)
+	if landscape {
+		rows = max(1, (int(float64(height)/scale)-CONTROLS_PADDING)/(BUTTON_HEIGHT+CONTROLS_PADDING))
+		columns = (buttons + rows - 1) / rows
+		return columns, (buttons + columns - 1) / columns
+	}
+	columns = max(1, (int(float64(width)/scale)-CONTROLS_PADDING)/(BUTTON_WIDTH+CONTROLS_PADDING))
+	rows = (buttons + columns - 1) / columns
+	return (buttons + rows - 1) / rows, rows
+}
+
+// Fits reports whether the layout is computed for the screen of the given size.
+func (l *Layout) Fits(width, height int, scale float64) bool {
+	return l.Width == width && l.Height == height && l.Scale == scale
+}
+
+// Equals returns true if both layouts place everything at the same positions.
+func (l *Layout) Equals(other *Layout) bool {
+	return (l.Width == other.Width &&
+		l.Height == other.Height &&
+		l.Scale == other.Scale &&
+		l.CellSize == other.CellSize &&
+		l.Stage.Eq(other.Stage) &&
+		l.Controls.Eq(other.Controls) &&
+		l.Columns == other.Columns &&
+		l.Branches.Eq(other.Branches) &&
+		l.History.Eq(other.History))
+}
+
+// Px converts logical pixels into device pixels.
+func (l *Layout) Px(v float32) float32 {
+	return v * float32(l.Scale)
+}
+
+// ToPoint converts a position on the screen into a cell on the board.
+func (l *Layout) ToPoint(p Position) hyper.Point {
+	local := p.Sub(Position{float32(l.Stage.Min.X), float32(l.Stage.Min.Y)})
+	return local.ToPoint(l.CellSize)
+}
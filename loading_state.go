@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// LOADING_BAR_WIDTH and LOADING_BAR_HEIGHT are the size of the progress bar in logical pixels.
+const (
+	LOADING_BAR_WIDTH  = 320 // px
+	LOADING_BAR_HEIGHT = 16  // px
+)
+
+// BuildFunc creates the state to switch to after assets are loaded.
+type BuildFunc func(r *ResourceLoader, themes []*Theme) (State, error)
+
+// LoadingState preloads assets in the background while showing a progress bar.
+// It switches to the state created by the BuildFunc when loading finishes, or to ErrorState if it fails.
+type LoadingState struct {
+	*StateMachine
+	*ResourceLoader
+	build  BuildFunc
+	themes []*Theme
+	loaded atomic.Int64
+	total  atomic.Int64
+	done   chan error
+	scale  float64
+}
+
+// NewLoadingState creates a LoadingState and starts loading in the background.
+func NewLoadingState(m *StateMachine, r *ResourceLoader, build BuildFunc) *LoadingState {
+	s := &LoadingState{
+		StateMachine:   m,
+		ResourceLoader: r,
+		build:          build,
+		done:           make(chan error, 1),
+		scale:          1,
+	}
+	go func() {
+		s.done <- s.load()
+	}()
+	return s
+}
+
+// load reads the themes and the files listed in their manifest.
+func (s *LoadingState) load() error {
+	s.themes = LoadThemes(s.ResourceLoader, THEMES_DIR)
+	m := NewManifest(s.ResourceLoader, s.themes)
+	s.total.Store(int64(m.Len()))
+
+	step := func(path string, err error) error {
+		if err != nil {
+			return fmt.Errorf("unable to load %s: %w", path, err)
+		}
+		s.loaded.Add(1)
+		// let the game loop draw the progress, especially in browsers
+		runtime.Gosched()
+		return nil
+	}
+	for _, p := range m.Images {
+		_, err := s.ResourceLoader.Image(p)
+		if err := step(p, err); err != nil {
+			return err
+		}
+	}
+	for _, p := range m.Fonts {
+		_, err := s.ResourceLoader.FontFaceSource(p)
+		if err := step(p, err); err != nil {
+			return err
+		}
+	}
+	for _, p := range m.Sounds {
+		_, err := s.ResourceLoader.Sound(p)
+		if err := step(p, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Progress returns the ratio of loaded files in [0, 1].
+func (s *LoadingState) Progress() float32 {
+	total := s.total.Load()
+	if total == 0 {
+		return 0
+	}
+	return float32(s.loaded.Load()) / float32(total)
+}
+
+// Resize records the device scale factor to draw the progress bar.
+func (s *LoadingState) Resize(width, height int, scale float64) {
+	s.scale = scale
+}
+
+// Update switches to the next state once loading finishes.
+func (s *LoadingState) Update() error {
+	select {
+	case err := <-s.done:
+		if err != nil {
+			s.StateMachine.Switch(NewErrorState(err))
+			return nil
+		}
+		next, err := s.build(s.ResourceLoader, s.themes)
+		if err != nil {
+			s.StateMachine.Switch(NewErrorState(err))
+			return nil
+		}
+		s.StateMachine.Switch(next)
+	default:
+	}
+	return nil
+}
+
+// Draw renders the progress bar at the center of the screen.
+func (s *LoadingState) Draw(screen *ebiten.Image) {
+	t := DefaultTheme
+	screen.Fill(t.Background)
+
+	scale := float32(s.scale)
+	bounds := screen.Bounds()
+	w, h := LOADING_BAR_WIDTH*scale, LOADING_BAR_HEIGHT*scale
+	x := (float32(bounds.Dx()) - w) / 2
+	y := (float32(bounds.Dy()) - h) / 2
+	vector.DrawFilledRect(screen, x, y, w, h, t.Grid, false)
+	vector.DrawFilledRect(screen, x, y, w*s.Progress(), h, t.Robots[hyper.Blue], false)
+	vector.StrokeRect(screen, x, y, w, h, scale, t.Wall, false)
+
+	face, err := s.ResourceLoader.FontFace(int(12 * scale))
+	if err != nil {
+		return
+	}
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(x+w/2), float64(y-8*scale))
+	op.ColorScale.ScaleWithColor(color.Color(t.Wall))
+	op.PrimaryAlign = text.AlignCenter
+	op.SecondaryAlign = text.AlignEnd
+	text.Draw(screen, "Loading...", face, op)
+}
@@ -3,63 +3,152 @@
 package main
 
 import (
+	"flag"
+	"log"
+
 	"github.com/fj68/hyper-tux-go/hyper"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
-// CONTROLS_HEIGHT is the height of the controls panel in pixels including padding.
-const CONTROLS_HEIGHT = 64 + 16 // px incl. padding
-// STAGE_WIDTH is the width of the game board in pixels.
-const STAGE_WIDTH = 640 - CONTROLS_HEIGHT // px
-// STAGE_HEIGHT is the height of the game board in pixels.
-const STAGE_HEIGHT = 640 - CONTROLS_HEIGHT // px
-// CELL_SIZE is the width and height of each cell in the grid in pixels.
-const CELL_SIZE float32 = STAGE_WIDTH / 16
+// DEFAULT_SCREEN_WIDTH and DEFAULT_SCREEN_HEIGHT are the size of the window on startup in pixels.
+const (
+	DEFAULT_SCREEN_WIDTH  = 960 // px
+	DEFAULT_SCREEN_HEIGHT = 640 // px
+)
+
+// DefaultMap is the wall bits of each cell of the map of random games and daily puzzles.
+var DefaultMap = [][]int{
+	{0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0},
+}
 
 // Game is the main game struct that implements ebiten.Game interface.
 type Game struct {
 	State
+	Machine  *StateMachine
+	Autosave *Autosaver // or nil if the storage is not available
+	Profile  *Profile   // or nil if the storage is not available
 }
 
-// Layout returns the logical screen dimensions for the game window.
+// Update updates the current state, records the stats and autosaves the game in progress.
+// The game is saved before the window is closed.
+func (g *Game) Update() error {
+	if err := g.State.Update(); err != nil {
+		return err
+	}
+	if g.Autosave == nil {
+		return nil
+	}
+	// test plays of the editor are neither recorded nor saved
+	if s, ok := g.Machine.Current.(*GameState); ok && s.editor == nil {
+		g.Profile.Update(s.Board)
+		g.Autosave.Update(s)
+	}
+	if ebiten.IsWindowBeingClosed() {
+		g.Autosave.Flush()
+		return ebiten.Termination
+	}
+	return nil
+}
+
+// Layout returns the screen dimensions in device pixels so that the game is rendered sharply on high-DPI displays.
+// The current state is notified of the new size if it follows the size of the screen.
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return 640, 640
+	scale := 1.0
+	if m := ebiten.Monitor(); m != nil {
+		scale = m.DeviceScaleFactor()
+	}
+	screenWidth = int(float64(outsideWidth) * scale)
+	screenHeight = int(float64(outsideHeight) * scale)
+	if r, ok := g.State.(Resizer); ok {
+		r.Resize(screenWidth, screenHeight, scale)
+	}
+	return
 }
 
-func main() {
-	s, err := NewGameState(hyper.Size{W: 16, H: 16})
+// newGameState creates the game of the puzzle of the code, or a random game on the map if the code is empty or invalid.
+func newGameState(r *ResourceLoader, themes []*Theme, m *hyper.Mapdata, code string) (*GameState, error) {
+	if code != "" {
+		b, err := hyper.NewBoardFromCode(code, DefaultPlacement)
+		if err == nil {
+			return NewGameStateFromBoard(b, r, themes, NewSettings())
+		}
+		log.Println(err)
+	}
+	s, err := NewGameStateWithAssets(hyper.Size{W: m.W, H: m.H}, r, themes)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	s.Board.Mapdata = m
+	return s, nil
+}
 
-	m, err := hyper.NewMapdataFromSlice([][]int{
-		{0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0},
-	})
+func main() {
+	code := flag.String("puzzle", "", "code of the puzzle to start, which is shared by the Share button")
+	flag.Parse()
+
+	m, err := hyper.NewMapdataFromSlice(DefaultMap)
 	if err != nil {
 		panic(err)
 	}
-	s.Board.Mapdata = m
 
-	game := &Game{&StateMachine{Current: s}}
+	var autosave *Autosaver
+	var profile *Profile
+	if storage, err := NewStorage(); err != nil {
+		log.Println(err)
+	} else {
+		autosave = NewAutosaver(storage)
+		profile = LoadProfile(storage)
+	}
+
+	machine := &StateMachine{}
+	r := NewResourceLoader(DefaultAssets()...)
+	machine.Switch(NewLoadingState(machine, r, func(r *ResourceLoader, themes []*Theme) (State, error) {
+		s, err := newGameState(r, themes, m, *code)
+		if err != nil {
+			return nil, err
+		}
+		s.Machine = machine
+		s.Profile = profile
+		if autosave != nil {
+			s.Storage = autosave.Storage
+		}
+		if backend, err := NewEbitenAudioBackend(r); err != nil {
+			log.Println(err)
+		} else {
+			s.Audio.Backend = backend
+		}
+		// offer to resume the last game unless a puzzle is given
+		if autosave != nil && *code == "" {
+			if saved, b, ok := autosave.Resumable(); ok {
+				return NewResumeState(s, saved, b)
+			}
+		}
+		return s, nil
+	}))
+	bridge := NewBridge(machine)
+	exposeBridge(bridge)
+	game := &Game{State: bridge, Machine: machine, Autosave: autosave, Profile: profile}
 
-	ebiten.SetWindowSize(640, 640)
+	ebiten.SetWindowSize(DEFAULT_SCREEN_WIDTH, DEFAULT_SCREEN_HEIGHT)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	ebiten.SetWindowTitle("Hyper Tux")
+	ebiten.SetWindowClosingHandled(autosave != nil)
 
 	if err := ebiten.RunGame(game); err != nil {
 		panic(err)
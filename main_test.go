@@ -12,10 +12,6 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
-const (
-	CELL_SIZE = main.CELL_SIZE
-)
-
 func TestGameState(t *testing.T) {
 	size := hyper.Size{W: 16, H: 16}
 	s, err := main.NewGameState(size)
@@ -50,6 +46,9 @@ func TestGameState(t *testing.T) {
 	s.Board.Actors[hyper.Blue].Point = hyper.Point{X: 1, Y: 3}
 	s.Board.Actors[hyper.Yellow].Point = hyper.Point{X: 5, Y: 2}
 
+	// moves are buffered while an actor is sliding, so turn animations off to apply all swipes in a single update
+	s.Settings.AnimationSpeed = main.AnimationOff
+
 	g := &main.StateMachine{Current: s}
 
 	s.SwipeEventDispatcher.Push(&main.SwipeEvent{
@@ -73,7 +72,7 @@ func TestGameState(t *testing.T) {
 		End:   hyper.Point{X: 3, Y: 2},
 	})
 
-	image := ebiten.NewImage(640, 640)
+	image := ebiten.NewImage(main.DEFAULT_SCREEN_WIDTH, main.DEFAULT_SCREEN_HEIGHT)
 
 	if err := g.Update(); err != nil {
 		t.Error(err)
@@ -85,6 +84,58 @@ func TestGameState(t *testing.T) {
 	}
 }
 
+func TestTouchEventHandler(t *testing.T) {
+	l := main.NewLayout(main.DEFAULT_SCREEN_WIDTH, main.DEFAULT_SCREEN_HEIGHT, 1, hyper.Size{W: 16, H: 16}, 1)
+	origin := main.Position{X: float32(l.Stage.Min.X), Y: float32(l.Stage.Min.Y)}
+
+	// a touch pressed at the center of (2, 2) and dragged east by three cells, replayed frame by frame
+	var pressed, released []ebiten.TouchID
+	pos := origin.Add(main.Position{X: 2.5 * l.CellSize, Y: 2.5 * l.CellSize})
+	h := &main.TouchEventHandler{Input: &main.TouchInput{
+		AppendJustPressedTouchIDs:  func(ids []ebiten.TouchID) []ebiten.TouchID { return append(ids, pressed...) },
+		AppendJustReleasedTouchIDs: func(ids []ebiten.TouchID) []ebiten.TouchID { return append(ids, released...) },
+		TouchPressDuration: func(ebiten.TouchID) int {
+			if released != nil {
+				return 0
+			}
+			return 1
+		},
+		TouchPosition: func(ebiten.TouchID) (int, int) {
+			if released != nil {
+				return 0, 0
+			}
+			return int(pos.X), int(pos.Y)
+		},
+		TouchPositionInPreviousTick: func(ebiten.TouchID) (int, int) {
+			return int(pos.X), int(pos.Y)
+		},
+	}}
+	d := main.NewSwipeEventDispatcher(h)
+	d.Layout = l
+
+	pressed = []ebiten.TouchID{1}
+	d.Update()
+	pressed = nil
+	for range 6 {
+		pos = pos.Add(main.Position{X: l.CellSize / 2})
+		d.Update()
+	}
+	// the released touch is at (0, 0), while its last position is kept for the previous tick
+	released = []ebiten.TouchID{1}
+	d.Update()
+
+	e := d.Pop()
+	if e == nil {
+		t.Fatal("expected a swipe")
+	}
+	if expected := (hyper.Point{X: 2, Y: 2}); !e.Start.Equals(expected) {
+		t.Errorf("start: expected %v, actual %v", expected, e.Start)
+	}
+	if e.Direction() != hyper.East {
+		t.Errorf("direction: expected %v, actual %v", hyper.East, e.Direction())
+	}
+}
+
 func TestMain(m *testing.M) {
 	snapshot_test.RunTestGame(m)
 }
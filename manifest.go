@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Manifest lists the asset files to be loaded before the game starts.
+type Manifest struct {
+	Images []string
+	Fonts  []string
+	Sounds []string
+}
+
+// NewManifest creates and returns a Manifest of the files referenced by the themes and the sound files.
+func NewManifest(r *ResourceLoader, themes []*Theme) *Manifest {
+	m := &Manifest{}
+	entries, err := fs.ReadDir(r.FS(), SOUNDS_DIR)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Println(err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".wav") {
+			m.Sounds = appendPath(m.Sounds, path.Join(SOUNDS_DIR, entry.Name()))
+		}
+	}
+	for _, t := range themes {
+		m.Fonts = appendPath(m.Fonts, t.Font)
+		m.Images = appendPath(m.Images, t.Button.IdleImage)
+		m.Images = appendPath(m.Images, t.Button.HoverImage)
+		m.Images = appendPath(m.Images, t.Button.PressedImage)
+		for _, p := range t.Sprites {
+			m.Images = appendPath(m.Images, p)
+		}
+	}
+	return m
+}
+
+// Len returns the number of files in the manifest.
+func (m *Manifest) Len() int {
+	return len(m.Images) + len(m.Fonts) + len(m.Sounds)
+}
+
+// appendPath appends the path to paths unless it is empty or already included.
+func appendPath(paths []string, p string) []string {
+	if p == "" || slices.Contains(paths, p) {
+		return paths
+	}
+	return append(paths, p)
+}
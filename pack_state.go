@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/ebitenui/ebitenui"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// PACKS_DIR is the directory in the assets to load puzzle packs from.
+const PACKS_DIR = "packs"
+
+// PACKS_PROGRESS_NAME is the name of the progress of packs in the storage.
+const PACKS_PROGRESS_NAME = "packs.json"
+
+// THUMBNAIL_SIZE and THUMBNAIL_SPACING are the size of thumbnails of puzzles and the space around them in logical pixels.
+const (
+	THUMBNAIL_SIZE    = 120 // px
+	THUMBNAIL_SPACING = 32  // px
+)
+
+// LoadPacks reads all puzzle packs in the directory, skipping invalid ones.
+func LoadPacks(r *ResourceLoader, dir string) []*hyper.Pack {
+	packs := []*hyper.Pack{}
+	entries, err := fs.ReadDir(r.FS(), dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Println(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		filename := path.Join(dir, entry.Name())
+		file, err := r.File(filename)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		p, err := hyper.ReadPack(file)
+		if err != nil {
+			log.Printf("error in %s: %v", filename, err)
+			continue
+		}
+		packs = append(packs, p)
+	}
+	return packs
+}
+
+// PackRun is a puzzle of a pack played in a game.
+type PackRun struct {
+	Browser *PackState // browser to return to when the puzzle is solved
+	Index   int        // index of the puzzle in the current pack of the browser
+}
+
+// PackState browses puzzle packs as thumbnails of their puzzles with completion marks.
+// Puzzles are unlocked in order, and clicking an unlocked one plays it.
+type PackState struct {
+	UI         *ebitenui.UI
+	Storage    Storage    // to keep the progress, or nil
+	game       *GameState // game to return to, which also starts puzzles
+	packs      []*hyper.Pack
+	progress   map[string]hyper.PackProgress // by title of packs
+	current    int                           // index of the pack shown
+	thumbnails []*ebiten.Image               // of puzzles of the current pack
+	width      int
+	height     int
+	scale      float64
+	resized    bool // whether the UI is to be rebuilt on the next update
+}
+
+// NewPackState creates a browser of the packs, returning to the game which must have Machine.
+func NewPackState(game *GameState, packs []*hyper.Pack, s Storage) (*PackState, error) {
+	p := &PackState{
+		Storage:  s,
+		game:     game,
+		packs:    packs,
+		progress: map[string]hyper.PackProgress{},
+		width:    DEFAULT_SCREEN_WIDTH,
+		height:   DEFAULT_SCREEN_HEIGHT,
+		scale:    1,
+	}
+	p.load()
+	if err := p.applyLayout(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// load reads the progress from the storage.
+func (p *PackState) load() {
+	if p.Storage == nil {
+		return
+	}
+	data, err := p.Storage.Load(PACKS_PROGRESS_NAME)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println(err)
+		}
+		return
+	}
+	if err := json.Unmarshal(data, &p.progress); err != nil {
+		log.Println(err)
+	}
+}
+
+// Record records the puzzle of the current pack solved in the moves, and keeps the progress in the storage.
+func (p *PackState) Record(i, moves int) {
+	if !p.progressOf(p.pack()).Record(i, moves) || p.Storage == nil {
+		return
+	}
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(p.progress); err != nil {
+		log.Println(err)
+		return
+	}
+	if err := p.Storage.Store(PACKS_PROGRESS_NAME, buf.Bytes()); err != nil {
+		log.Println(err)
+	}
+}
+
+// pack returns the pack shown, or nil if there are no packs.
+func (p *PackState) pack() *hyper.Pack {
+	if len(p.packs) == 0 {
+		return nil
+	}
+	return p.packs[p.current]
+}
+
+// show shows the pack of the title, and reports whether it is found.
+func (p *PackState) show(title string) bool {
+	for i, pack := range p.packs {
+		if pack.Title == title {
+			p.current = i
+			p.clearThumbnails()
+			return true
+		}
+	}
+	return false
+}
+
+// progressOf returns the progress of the pack, creating it if needed.
+func (p *PackState) progressOf(pack *hyper.Pack) hyper.PackProgress {
+	progress, ok := p.progress[pack.Title]
+	if !ok {
+		progress = hyper.PackProgress{}
+		p.progress[pack.Title] = progress
+	}
+	return progress
+}
+
+// Resize schedules to rebuild the UI and the thumbnails for the new screen size on the next update.
+func (p *PackState) Resize(width, height int, scale float64) {
+	if p.width != width || p.height != height || p.scale != scale {
+		p.width, p.height, p.scale = width, height, scale
+		p.resized = true
+	}
+}
+
+// applyLayout rebuilds the UI and the thumbnails to fit the screen.
+func (p *PackState) applyLayout() error {
+	ui, err := p.createUI()
+	if err != nil {
+		return err
+	}
+	p.UI = ui
+	p.clearThumbnails()
+	return nil
+}
+
+// clearThumbnails discards the thumbnails so that they are rendered again.
+func (p *PackState) clearThumbnails() {
+	for _, img := range p.thumbnails {
+		img.Deallocate()
+	}
+	p.thumbnails = nil
+}
+
+// turn shows the pack next to the current one by the step, wrapping around.
+func (p *PackState) turn(step int) {
+	if len(p.packs) == 0 {
+		return
+	}
+	n := len(p.packs)
+	p.current = ((p.current+step)%n + n) % n
+	p.clearThumbnails()
+}
+
+// Update handles clicks on thumbnails and the UI, and returns to the game on Escape.
+func (p *PackState) Update() error {
+	if p.resized {
+		p.resized = false
+		if err := p.applyLayout(); err != nil {
+			return err
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		p.back()
+		return nil
+	}
+	if i, ok := p.clicked(); ok {
+		return p.play(i)
+	}
+	p.UI.Update()
+	return nil
+}
+
+// back returns to the game.
+func (p *PackState) back() {
+	p.game.Machine.Switch(p.game)
+}
+
+// play starts the i-th puzzle of the current pack if it is unlocked.
+func (p *PackState) play(i int) error {
+	pack := p.pack()
+	if !p.progressOf(pack).Unlocked(i) {
+		p.game.emit(&GameEvent{Kind: BumpEvent})
+		return nil
+	}
+	b, err := pack.Puzzles[i].NewBoard(DefaultPlacement)
+	if err != nil {
+		return err
+	}
+	next, err := p.game.play(b)
+	if err != nil {
+		return err
+	}
+	next.Pack = &PackRun{Browser: p, Index: i}
+	return nil
+}
+
+// grid returns the number of columns of thumbnails and the size of a cell of the grid in device pixels.
+func (p *PackState) grid() (columns int, cell float32) {
+	cell = float32((THUMBNAIL_SIZE + THUMBNAIL_SPACING) * p.scale)
+	columns = max(1, int(float32(p.width)/cell))
+	return columns, cell
+}
+
+// thumbnailRect returns the area of the i-th thumbnail on the screen.
+func (p *PackState) thumbnailRect(i int) image.Rectangle {
+	columns, cell := p.grid()
+	n := len(p.pack().Puzzles)
+	rows := (n + columns - 1) / columns
+	width := cell * float32(min(n, columns))
+	left := (float32(p.width) - width) / 2
+	top := (float32(p.height) - cell*float32(rows)) / 2
+	margin := float32(THUMBNAIL_SPACING*p.scale) / 2
+	size := int(THUMBNAIL_SIZE * p.scale)
+	x := int(left + cell*float32(i%columns) + margin)
+	y := int(top + cell*float32(i/columns) + margin)
+	return image.Rect(x, y, x+size, y+size)
+}
+
+// clicked returns the index of the thumbnail which is just clicked or touched, if any.
+func (p *PackState) clicked() (int, bool) {
+	if p.pack() == nil {
+		return 0, false
+	}
+	points := []image.Point{}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		points = append(points, image.Pt(ebiten.CursorPosition()))
+	}
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		points = append(points, image.Pt(ebiten.TouchPosition(id)))
+	}
+	for _, pt := range points {
+		if i, ok := p.thumbnailAt(pt); ok {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// thumbnailAt returns the index of the thumbnail at the point on the screen, if any.
+func (p *PackState) thumbnailAt(pt image.Point) (int, bool) {
+	for i := range p.pack().Puzzles {
+		if pt.In(p.thumbnailRect(i)) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// thumbnail returns the image of the initial state of the puzzle, rendered with cells fitting in THUMBNAIL_SIZE.
+func (p *PackState) thumbnail(puzzle *hyper.PackPuzzle) *ebiten.Image {
+	b, err := puzzle.NewBoard(hyper.Placement{})
+	if err != nil {
+		// puzzles are validated on loading
+		log.Println(err)
+		return ebiten.NewImage(1, 1)
+	}
+	size := float32(THUMBNAIL_SIZE * p.scale)
+	cellSize := size / float32(max(b.Size.W, b.Size.H))
+	r := &Renderer{
+		ResourceLoader: p.game.ResourceLoader,
+		Settings:       p.game.Settings,
+		Theme:          p.game.theme(),
+		CellSize:       cellSize,
+		Scale:          p.scale,
+	}
+	img := ebiten.NewImage(int(cellSize*float32(b.Size.W)), int(cellSize*float32(b.Size.H)))
+	img.Fill(r.Theme.Background)
+	r.DrawBoard(img, b.Mapdata)
+	r.DrawGoal(img, b.Goal)
+	for _, actor := range b.Actors {
+		r.DrawActor(img, actor.Color, NewPosition(actor.Point, cellSize), false)
+	}
+	r.DrawBorder(img)
+	return img
+}
+
+// Draw renders the title of the pack, the thumbnails with their marks, the par and the hint of the hovered puzzle, and the UI.
+func (p *PackState) Draw(screen *ebiten.Image) {
+	t := p.game.theme()
+	screen.Fill(t.Background)
+	r := p.game.renderer
+	px := func(v float32) float32 { return v * float32(p.scale) }
+
+	pack := p.pack()
+	if pack == nil {
+		center := Position{float32(p.width) / 2, float32(p.height) / 2}
+		r.DrawLetter(screen, "No puzzle packs", center, px(16), t.Wall)
+		p.UI.Draw(screen)
+		return
+	}
+	title := fmt.Sprintf("%s (%d/%d)", pack.Title, p.current+1, len(p.packs))
+	if pack.Author != "" {
+		title += " by " + pack.Author
+	}
+	r.DrawLetter(screen, title, Position{float32(p.width) / 2, px(24)}, px(16), t.Wall)
+
+	if p.thumbnails == nil {
+		for _, puzzle := range pack.Puzzles {
+			p.thumbnails = append(p.thumbnails, p.thumbnail(puzzle))
+		}
+	}
+	progress := p.progressOf(pack)
+	for i, puzzle := range pack.Puzzles {
+		rect := p.thumbnailRect(i)
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
+		screen.DrawImage(p.thumbnails[i], op)
+
+		label := fmt.Sprintf("%d", i+1)
+		switch {
+		case !progress.Unlocked(i):
+			vector.DrawFilledRect(screen, float32(rect.Min.X), float32(rect.Min.Y), float32(rect.Dx()), float32(rect.Dy()), Translucent(t.Background, 192), false)
+			label += " Locked"
+		case progress.Solved(i) && progress[i] <= puzzle.Par:
+			label += fmt.Sprintf(" Par %d/%d", progress[i], puzzle.Par)
+		case progress.Solved(i):
+			label += fmt.Sprintf(" Solved %d/%d", progress[i], puzzle.Par)
+		}
+		below := Position{float32(rect.Min.X+rect.Max.X) / 2, float32(rect.Max.Y) + px(10)}
+		r.DrawLetter(screen, label, below, px(12), t.Wall)
+	}
+
+	if i, ok := p.thumbnailAt(image.Pt(ebiten.CursorPosition())); ok && progress.Unlocked(i) {
+		puzzle := pack.Puzzles[i]
+		info := fmt.Sprintf("Puzzle %d: par %d", i+1, puzzle.Par)
+		if puzzle.Hint != "" {
+			info += " - " + puzzle.Hint
+		}
+		r.DrawLetter(screen, info, Position{float32(p.width) / 2, float32(p.height) - px(72)}, px(12), t.Wall)
+	}
+
+	p.UI.Draw(screen)
+}
+
+// createUI creates and returns the UI container with the buttons at the bottom of the screen.
+func (p *PackState) createUI() (*ebitenui.UI, error) {
+	root := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewAnchorLayout()),
+	)
+	btnContainer := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(3),
+			widget.GridLayoutOpts.Spacing(int(8*p.scale), int(8*p.scale)),
+		)),
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
+			HorizontalPosition: widget.AnchorLayoutPositionCenter,
+			VerticalPosition:   widget.AnchorLayoutPositionEnd,
+			Padding:            widget.Insets{Bottom: int(16 * p.scale)},
+		})),
+	)
+	root.AddChild(btnContainer)
+
+	buttons := []struct {
+		label   string
+		onclick func()
+	}{
+		{"Prev Pack", func() { p.turn(-1) }},
+		{"Next Pack", func() { p.turn(1) }},
+		{"Back", p.back},
+	}
+	for _, b := range buttons {
+		onclick := b.onclick
+		clicked := func(args *widget.ButtonClickedEventArgs) {
+			p.game.emit(&GameEvent{Kind: ClickEvent})
+			onclick()
+		}
+		btn, err := createButton(p.game.ResourceLoader, &p.game.theme().Button, p.scale, b.label, clicked)
+		if err != nil {
+			return nil, err
+		}
+		btnContainer.AddChild(btn)
+	}
+
+	return &ebitenui.UI{
+		Container: root,
+	}, nil
+}
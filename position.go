@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/fj68/hyper-tux-go/hyper"
 )
@@ -19,8 +20,8 @@ func NewPosition(p hyper.Point, cellSize float32) Position {
 // ToPoint converts screen coordinates to a grid point given a cell size.
 func (p *Position) ToPoint(cellSize float32) hyper.Point {
 	return hyper.Point{
-		X: int(p.X / cellSize),
-		Y: int(p.Y / cellSize),
+		X: int(math.Floor(float64(p.X / cellSize))),
+		Y: int(math.Floor(float64(p.Y / cellSize))),
 	}
 }
 
@@ -48,6 +49,11 @@ func (p *Position) Mul(other Position) Position {
 	}
 }
 
+// Len returns the distance from the origin to this position.
+func (p *Position) Len() float32 {
+	return float32(math.Hypot(float64(p.X), float64(p.Y)))
+}
+
 // Equals returns true if this position and another position have the same coordinates.
 func (p *Position) Equals(other Position) bool {
 	return p.X == other.X && p.Y == other.Y
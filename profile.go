@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"log"
+	"time"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+)
+
+// PROFILE_NAME is the name of the stats of the player in the storage.
+const PROFILE_NAME = "stats.json"
+
+// Profile records the stats of the player on the boards of games, and keeps them in the storage.
+type Profile struct {
+	Storage
+	*hyper.StatsTracker
+}
+
+// LoadProfile reads the stats from the storage, or starts empty stats if none are stored.
+func LoadProfile(s Storage) *Profile {
+	stats := hyper.NewStats()
+	if data, err := s.Load(PROFILE_NAME); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println(err)
+		}
+	} else if loaded, err := hyper.ReadStats(bytes.NewReader(data)); err != nil {
+		log.Println(err)
+	} else {
+		stats = loaded
+	}
+	return &Profile{
+		Storage:      s,
+		StatsTracker: hyper.NewStatsTracker(stats, time.Now),
+	}
+}
+
+// Update follows the board, and stores the stats if they are changed.
+func (p *Profile) Update(b *hyper.Board) {
+	p.StatsTracker.Follow(b)
+	if !p.StatsTracker.Changed() {
+		return
+	}
+	buf := &bytes.Buffer{}
+	if err := p.StatsTracker.Stats.Write(buf); err != nil {
+		log.Println(err)
+		return
+	}
+	if err := p.Storage.Store(PROFILE_NAME, buf.Bytes()); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"image/color"
+	"log"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Renderer draws the elements of boards with the theme, so that every state shows boards in the same way.
+type Renderer struct {
+	*ResourceLoader
+	Settings *Settings
+	Theme    *Theme
+	CellSize float32 // in device pixels
+	Scale    float64 // device scale factor
+}
+
+// NewRenderer creates a Renderer drawing with the theme at the cell size of the layout.
+func NewRenderer(r *ResourceLoader, s *Settings, t *Theme, l *Layout) *Renderer {
+	return &Renderer{
+		ResourceLoader: r,
+		Settings:       s,
+		Theme:          t,
+		CellSize:       l.CellSize,
+		Scale:          l.Scale,
+	}
+}
+
+// Px converts logical pixels into device pixels.
+func (r *Renderer) Px(v float32) float32 {
+	return v * float32(r.Scale)
+}
+
+// DrawBorder renders the border around the image.
+func (r *Renderer) DrawBorder(screen *ebiten.Image) {
+	vector.StrokeRect(screen, 0, 0, float32(screen.Bounds().Dx()), float32(screen.Bounds().Dy()), r.Px(1), r.Theme.Wall, false)
+}
+
+// DrawBoard renders the grid, walls, and center box of the map.
+func (r *Renderer) DrawBoard(screen *ebiten.Image, m *hyper.Mapdata) {
+	cellSize := r.CellSize
+	width := r.Px(1)
+	t := r.Theme
+	// lines
+	for y := range m.H - 1 {
+		vector.StrokeLine(screen, 0, float32(y+1)*cellSize, float32(m.W)*cellSize, float32(y+1)*cellSize, width, t.Grid, false)
+	}
+	for x := range m.W - 1 {
+		vector.StrokeLine(screen, float32(x+1)*cellSize, 0, float32(x+1)*cellSize, float32(m.H)*cellSize, width, t.Grid, false)
+	}
+	// walls
+	for y, rows := range m.VWalls {
+		for _, x := range rows {
+			vector.StrokeLine(screen, float32(x)*cellSize, float32(y)*cellSize, float32(x)*cellSize, float32(y+1)*cellSize, width, t.Wall, false)
+		}
+	}
+	for x, cols := range m.HWalls {
+		for _, y := range cols {
+			vector.StrokeLine(screen, float32(x)*cellSize, float32(y)*cellSize, float32(x+1)*cellSize, float32(y)*cellSize, width, t.Wall, false)
+		}
+	}
+	// center box
+	c := m.Center()
+	vector.DrawFilledRect(screen, float32(c.TopLeft.X)*cellSize, float32(c.TopLeft.Y)*cellSize, float32(c.Size().W)*cellSize-width, float32(c.Size().H)*cellSize-width, t.Center, false)
+}
+
+// DrawActor renders an actor in the cell whose top-left corner is at p as a colored circle, with a thick border when selected.
+// The sprite of the theme is drawn instead if available.
+// In accessible mode, the actor is drawn as its shape with its letter instead.
+func (r *Renderer) DrawActor(screen *ebiten.Image, c hyper.Color, p Position, selected bool) {
+	halfCellSize := r.CellSize / 2
+	p = p.Add(Position{halfCellSize, halfCellSize})
+	radius := halfCellSize - r.Px(2)
+	t := r.Theme
+	clr := t.Palette().Color(c)
+	if sprite, ok := t.Sprite(r.ResourceLoader, c); ok {
+		r.DrawSprite(screen, sprite, p, radius)
+	} else if r.Settings.Accessible {
+		shape := ShapeOf(c)
+		DrawFilledShape(screen, shape, p.X, p.Y, radius, clr)
+		StrokeShape(screen, shape, p.X, p.Y, radius, r.Px(1), t.Outline)
+	} else {
+		vector.DrawFilledCircle(screen, p.X, p.Y, radius, clr, true)
+		vector.StrokeCircle(screen, p.X, p.Y, radius, r.Px(1), t.Outline, true)
+	}
+	if r.Settings.Accessible {
+		r.DrawLetter(screen, Letter(c), p, radius, Contrast(clr))
+	}
+	if selected {
+		vector.StrokeCircle(screen, p.X, p.Y, radius, r.Px(3), t.Outline, true)
+	}
+}
+
+// DrawSprite renders the image scaled to fit in the circle of radius r centered at p.
+func (r *Renderer) DrawSprite(screen *ebiten.Image, sprite *ebiten.Image, p Position, radius float32) {
+	size := sprite.Bounds().Size()
+	scale := 2 * float64(radius) / float64(max(size.X, size.Y))
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-float64(size.X)/2, -float64(size.Y)/2)
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(float64(p.X), float64(p.Y))
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(sprite, op)
+}
+
+// DrawRecord renders a single move record as a line in the actor's color.
+// In accessible mode, the end of the line is marked with the shape of the actor.
+func (r *Renderer) DrawRecord(screen *ebiten.Image, record *hyper.Record) {
+	t := r.Theme
+	lineColor := t.Palette().Color(record.Color)
+	offset := r.Px(t.Offset(record.Color))
+	start := adjust(offset, record.Start, r.CellSize)
+	end := adjust(offset, record.End, r.CellSize)
+	vector.StrokeLine(screen, start.X, start.Y, end.X, end.Y, r.Px(1), lineColor, false)
+	if r.Settings.Accessible {
+		// mark the end of the trail with the shape of the actor
+		shape := ShapeOf(record.Color)
+		radius := r.CellSize / 8
+		DrawFilledShape(screen, shape, end.X, end.Y, radius, lineColor)
+		StrokeShape(screen, shape, end.X, end.Y, radius, r.Px(1), t.Outline)
+	}
+}
+
+// adjust returns the center of the cell shifted by n.
+func adjust(n float32, p hyper.Point, cellSize float32) Position {
+	diff := n + cellSize/2
+	pos := NewPosition(p, cellSize)
+	return pos.Add(Position{diff, diff})
+}
+
+// DrawGoal renders the goal as a colored rectangle.
+// In accessible mode, the outline of its shape and its letter are drawn on it.
+func (r *Renderer) DrawGoal(screen *ebiten.Image, goal hyper.Goal) {
+	cellSize := r.CellSize
+	c := r.Theme.Palette().Color(goal.Color)
+	vector.DrawFilledRect(screen, float32(goal.X)*cellSize, float32(goal.Y)*cellSize, cellSize-r.Px(1), cellSize-r.Px(1), c, false)
+	if r.Settings.Accessible {
+		p := NewPosition(goal.Point, cellSize)
+		p = p.Add(Position{cellSize / 2, cellSize / 2})
+		radius := cellSize/2 - r.Px(4)
+		StrokeShape(screen, ShapeOf(goal.Color), p.X, p.Y, radius, r.Px(2), Contrast(c))
+		r.DrawLetter(screen, Letter(goal.Color), p, radius*2/3, Contrast(c))
+	}
+}
+
+// DrawLetter renders a letter of the given size centered at p.
+func (r *Renderer) DrawLetter(screen *ebiten.Image, letter string, p Position, size float32, clr color.Color) {
+	face, err := r.ResourceLoader.FontFace(int(size))
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(float64(p.X), float64(p.Y))
+	op.ColorScale.ScaleWithColor(clr)
+	op.PrimaryAlign = text.AlignCenter
+	op.SecondaryAlign = text.AlignCenter
+	text.Draw(screen, letter, face, op)
+}
@@ -1,82 +1,169 @@
 package main
 
 import (
-	"bufio"
+	"archive/zip"
 	"bytes"
+	"embed"
 	_ "image/jpeg"
 	_ "image/png"
 	"io"
+	"io/fs"
+	"log"
 	"os"
+	"path/filepath"
+	"sync"
 
+	"github.com/fj68/hyper-tux-go/internal/layerfs"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"golang.org/x/image/font/gofont/goregular"
 )
 
-// ResourceLoader manages caching of game resources like images and fonts.
+// MODS_DIR is the directory to look up files and zip asset packs overriding the built-in assets.
+const MODS_DIR = "mods"
+
+// DEFAULT_FONT is the name of the built-in font.
+const DEFAULT_FONT = "goregular.TTF"
+
+//go:embed assets
+var embeddedAssets embed.FS
+
+// DefaultAssets returns the layers of asset sources, the uppermost first:
+// files in MODS_DIR, zip asset packs in MODS_DIR and the built-in assets.
+// Sources which are not available, e.g. in browsers, are skipped.
+func DefaultAssets() []fs.FS {
+	layers := []fs.FS{}
+	if info, err := os.Stat(MODS_DIR); err == nil && info.IsDir() {
+		layers = append(layers, os.DirFS(MODS_DIR))
+		packs, err := filepath.Glob(filepath.Join(MODS_DIR, "*.zip"))
+		if err != nil {
+			log.Println(err)
+		}
+		for _, name := range packs {
+			// asset packs are kept open while the game is running
+			z, err := zip.OpenReader(name)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			layers = append(layers, z)
+		}
+	}
+	builtin, err := fs.Sub(embeddedAssets, "assets")
+	if err != nil {
+		log.Fatal(err)
+	}
+	return append(layers, builtin)
+}
+
+// cache is a map of loaded resources which is safe for concurrent use.
+type cache[T any] struct {
+	mu     sync.Mutex
+	values map[string]T
+}
+
+// get returns the resource for the key, calling load to create it if it is not cached yet.
+// The lock is not held while loading so that a slow resource does not block others.
+// Failures are not cached so that they can be retried.
+func (c *cache[T]) get(key string, load func() (T, error)) (T, error) {
+	c.mu.Lock()
+	v, ok := c.values[key]
+	c.mu.Unlock()
+	if ok {
+		return v, nil
+	}
+
+	v, err := load()
+	if err != nil {
+		return v, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// keep the one loaded first if loaded concurrently
+	if cached, ok := c.values[key]; ok {
+		return cached, nil
+	}
+	if c.values == nil {
+		c.values = map[string]T{}
+	}
+	c.values[key] = v
+	return v, nil
+}
+
+// ResourceLoader loads game resources like images, fonts and sounds from layered asset sources and caches them.
+// Paths are slash-separated and relative to the root of the sources, e.g. "themes/classic.json".
+// It is safe for concurrent use, e.g. from a loading goroutine, except for setting Font.
 type ResourceLoader struct {
-	cache map[string]interface{}
+	fsys   fs.FS
+	images cache[*ebiten.Image]
+	fonts  cache[*text.GoTextFaceSource]
+	sounds cache[[]byte]
+	Font   string // path to the font file used by FontFace, or empty for Go Regular
 }
 
-// NewResourceLoader creates and returns a new ResourceLoader with an empty cache.
-func NewResourceLoader() *ResourceLoader {
+// NewResourceLoader creates and returns a new ResourceLoader which looks up files in the given sources in order.
+func NewResourceLoader(layers ...fs.FS) *ResourceLoader {
 	return &ResourceLoader{
-		cache: map[string]interface{}{},
+		fsys: layerfs.New(layers...),
 	}
 }
 
-// File returns an io.Reader for the file at the given path, using cache when available.
+// FS returns the file system of the merged asset sources.
+func (r *ResourceLoader) FS() fs.FS {
+	return r.fsys
+}
+
+// File returns an io.Reader for the content of the file at the given path.
 func (r *ResourceLoader) File(path string) (io.Reader, error) {
-	if v, ok := r.cache[path]; ok {
-		if b, ok := v.(io.Reader); ok {
-			return b, nil
-		}
-	}
-	file, err := os.Open(path)
+	data, err := fs.ReadFile(r.fsys, path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-	b := bufio.NewReader(file)
-	r.cache[path] = b
-	return b, nil
+	return bytes.NewReader(data), nil
 }
 
 // Image returns an ebiten.Image for the file at the given path, using cache when available.
 func (r *ResourceLoader) Image(path string) (*ebiten.Image, error) {
-	if v, ok := r.cache[path]; ok {
-		if i, ok := v.(*ebiten.Image); ok {
-			return i, nil
+	return r.images.get(path, func() (*ebiten.Image, error) {
+		file, err := r.File(path)
+		if err != nil {
+			return nil, err
 		}
-	}
-	file, err := r.File(path)
-	if err != nil {
-		return nil, err
-	}
-	i, _, err := ebitenutil.NewImageFromReader(file)
-	r.cache[path] = i
-	return i, err
+		i, _, err := ebitenutil.NewImageFromReader(file)
+		return i, err
+	})
 }
 
-// FontFaceSource returns a text.GoTextFaceSource for the given name and data, using cache when available.
-func (r *ResourceLoader) FontFaceSource(name string, data io.Reader) (*text.GoTextFaceSource, error) {
-	if v, ok := r.cache[name]; ok {
-		if f, ok := v.(*text.GoTextFaceSource); ok {
-			return f, nil
-		}
-	}
-	s, err := text.NewGoTextFaceSource(data)
-	if err != nil {
-		return nil, err
+// Sound returns the encoded data of the sound file at the given path, using cache when available.
+func (r *ResourceLoader) Sound(path string) ([]byte, error) {
+	return r.sounds.get(path, func() ([]byte, error) {
+		return fs.ReadFile(r.fsys, path)
+	})
+}
+
+// FontFaceSource returns a text.GoTextFaceSource for the font file at the given path, using cache when available.
+// DEFAULT_FONT or an empty path means the built-in Go Regular font.
+func (r *ResourceLoader) FontFaceSource(path string) (*text.GoTextFaceSource, error) {
+	if path == "" {
+		path = DEFAULT_FONT
 	}
-	r.cache[name] = s
-	return s, nil
+	return r.fonts.get(path, func() (*text.GoTextFaceSource, error) {
+		if path == DEFAULT_FONT {
+			return text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+		}
+		file, err := r.File(path)
+		if err != nil {
+			return nil, err
+		}
+		return text.NewGoTextFaceSource(file)
+	})
 }
 
-// FontFace returns a text.Face for the given size using the built-in Go Regular font.
+// FontFace returns a text.Face for the given size using Font, or the built-in Go Regular font if Font is empty.
 func (r *ResourceLoader) FontFace(size int) (text.Face, error) {
-	s, err := r.FontFaceSource("goregular.TTF", bytes.NewReader(goregular.TTF))
+	s, err := r.FontFaceSource(r.Font)
 	if err != nil {
 		return nil, err
 	}
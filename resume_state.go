@@ -0,0 +1,125 @@
+package main
+
+import (
+	"github.com/ebitenui/ebitenui"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ResumeState asks whether to resume the saved game in progress or to start the new game.
+type ResumeState struct {
+	UI      *ebitenui.UI
+	game    *GameState       // new game, which also starts the saved game
+	saved   *hyper.SavedGame // saved game to resume
+	board   *hyper.Board     // board of the saved game
+	width   int
+	height  int
+	scale   float64
+	resized bool // whether the UI is to be rebuilt on the next update
+}
+
+// NewResumeState creates a ResumeState offering to resume the saved game on the board instead of the game.
+// The game must have Machine to switch to the chosen game.
+func NewResumeState(game *GameState, saved *hyper.SavedGame, board *hyper.Board) (*ResumeState, error) {
+	s := &ResumeState{
+		game:   game,
+		saved:  saved,
+		board:  board,
+		width:  DEFAULT_SCREEN_WIDTH,
+		height: DEFAULT_SCREEN_HEIGHT,
+		scale:  1,
+	}
+	if err := s.applyLayout(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Resize schedules to rebuild the UI for the new screen size on the next update.
+func (s *ResumeState) Resize(width, height int, scale float64) {
+	if s.width != width || s.height != height || s.scale != scale {
+		s.width, s.height, s.scale = width, height, scale
+		s.resized = true
+	}
+}
+
+// applyLayout rebuilds the UI to fit the screen.
+func (s *ResumeState) applyLayout() error {
+	ui, err := s.createUI()
+	if err != nil {
+		return err
+	}
+	s.UI = ui
+	return nil
+}
+
+// Update handles the UI each frame.
+func (s *ResumeState) Update() error {
+	if s.resized {
+		s.resized = false
+		if err := s.applyLayout(); err != nil {
+			return err
+		}
+	}
+	s.UI.Update()
+	return nil
+}
+
+// Draw renders the question and the buttons.
+func (s *ResumeState) Draw(screen *ebiten.Image) {
+	t := s.game.theme()
+	screen.Fill(t.Background)
+	center := Position{float32(s.width) / 2, float32(s.height)/2 - float32(40*s.scale)}
+	s.game.renderer.DrawLetter(screen, "Resume the game in progress?", center, float32(16*s.scale), t.Wall)
+	s.UI.Draw(screen)
+}
+
+// createUI creates and returns the UI container with the buttons at the center of the screen.
+func (s *ResumeState) createUI() (*ebitenui.UI, error) {
+	root := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewAnchorLayout()),
+	)
+	btnContainer := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(2),
+			widget.GridLayoutOpts.Spacing(int(8*s.scale), int(8*s.scale)),
+		)),
+		widget.ContainerOpts.WidgetOpts(widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
+			HorizontalPosition: widget.AnchorLayoutPositionCenter,
+			VerticalPosition:   widget.AnchorLayoutPositionCenter,
+		})),
+	)
+	root.AddChild(btnContainer)
+
+	buttons := []struct {
+		label   string
+		onclick func() error
+	}{
+		{"Resume", func() error {
+			return s.game.resume(s.saved, s.board)
+		}},
+		{"New Game", func() error {
+			s.game.Machine.Switch(s.game)
+			return nil
+		}},
+	}
+	for _, b := range buttons {
+		onclick := b.onclick
+		clicked := func(args *widget.ButtonClickedEventArgs) {
+			s.game.emit(&GameEvent{Kind: ClickEvent})
+			if err := onclick(); err != nil {
+				s.game.Machine.Switch(NewErrorState(err))
+			}
+		}
+		btn, err := createButton(s.game.ResourceLoader, &s.game.theme().Button, s.scale, b.label, clicked)
+		if err != nil {
+			return nil, err
+		}
+		btnContainer.AddChild(btn)
+	}
+
+	return &ebitenui.UI{
+		Container: root,
+	}, nil
+}
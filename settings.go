@@ -0,0 +1,70 @@
+package main
+
+// REACH_MAX_MOVES is the maximum value of Settings.Reach.
+const REACH_MAX_MOVES = 3
+
+// VOLUME_STEP is the amount to lower the volume by each step, wrapping around to the maximum.
+const VOLUME_STEP = 0.25
+
+// AnimationSpeed represents how fast actors slide on the board.
+type AnimationSpeed int
+
+// AnimationSpeed constants.
+const (
+	AnimationNormal AnimationSpeed = iota
+	AnimationFast
+	AnimationOff
+)
+
+// String returns the string representation of the animation speed.
+func (s AnimationSpeed) String() string {
+	switch s {
+	case AnimationNormal:
+		return "Normal"
+	case AnimationFast:
+		return "Fast"
+	case AnimationOff:
+		return "Off"
+	}
+	return "unknown AnimationSpeed"
+}
+
+// Next returns the animation speed following this one, wrapping around.
+func (s AnimationSpeed) Next() AnimationSpeed {
+	return (s + 1) % (AnimationOff + 1)
+}
+
+// Settings holds user preferences of the game.
+type Settings struct {
+	AnimationSpeed
+	Accessible   bool    // whether actors and goals are marked with shapes and letters
+	HighContrast bool    // whether HighContrastPalette is used instead of the colors of actors of the theme
+	Theme        string  // name of the theme
+	Volume       float64 // volume of sounds in [0, 1]
+	Muted        bool
+	Reach        int  // maximum number of moves to highlight reachable cells, or 0 to disable
+	Analysis     bool // whether the number of moves to the goal is shown on each cell
+}
+
+// NewSettings creates and returns Settings with default values.
+func NewSettings() *Settings {
+	return &Settings{
+		AnimationSpeed: AnimationNormal,
+		Theme:          DefaultTheme.Name,
+		Volume:         1,
+		Reach:          1,
+	}
+}
+
+// NextReach returns the number of moves to highlight following the current one, wrapping around to 0.
+func (s *Settings) NextReach() int {
+	return (s.Reach + 1) % (REACH_MAX_MOVES + 1)
+}
+
+// NextVolume returns the volume one step lower than the current one, wrapping around to the maximum.
+func (s *Settings) NextVolume() float64 {
+	if s.Volume <= VOLUME_STEP {
+		return 1
+	}
+	return s.Volume - VOLUME_STEP
+}
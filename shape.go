@@ -0,0 +1,149 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Shape represents a glyph which distinguishes colors without relying on hues.
+type Shape int
+
+// Shape constants.
+const (
+	Circle Shape = iota
+	Triangle
+	Square
+	Diamond
+	Cross
+	Hexagon
+)
+
+// ShapeOf returns the shape for a given hyper.Color.
+func ShapeOf(c hyper.Color) Shape {
+	switch c {
+	case hyper.Red:
+		return Triangle
+	case hyper.Green:
+		return Square
+	case hyper.Blue:
+		return Diamond
+	case hyper.Yellow:
+		return Cross
+	case hyper.Black:
+		return Hexagon
+	}
+	return Circle
+}
+
+// Letter returns the single letter abbreviation for a given hyper.Color.
+func Letter(c hyper.Color) string {
+	switch c {
+	case hyper.Red:
+		return "R"
+	case hyper.Green:
+		return "G"
+	case hyper.Blue:
+		return "B"
+	case hyper.Yellow:
+		return "Y"
+	case hyper.Black:
+		return "K"
+	}
+	return "?"
+}
+
+// Path returns the outline of the shape centered at (x, y) which fits in a circle of radius r.
+func (s Shape) Path(x, y, r float32) *vector.Path {
+	path := &vector.Path{}
+	// regular polygon with n corners, the first one at the angle of rotation
+	polygon := func(n int, rotation float64) {
+		for i := range n {
+			angle := rotation + 2*math.Pi*float64(i)/float64(n)
+			px := x + r*float32(math.Cos(angle))
+			py := y + r*float32(math.Sin(angle))
+			if i == 0 {
+				path.MoveTo(px, py)
+			} else {
+				path.LineTo(px, py)
+			}
+		}
+		path.Close()
+	}
+
+	switch s {
+	case Circle:
+		path.Arc(x, y, r, 0, 2*math.Pi, vector.Clockwise)
+		path.Close()
+	case Triangle:
+		polygon(3, -math.Pi/2)
+	case Square:
+		polygon(4, math.Pi/4)
+	case Diamond:
+		polygon(4, 0)
+	case Cross:
+		// plus sign
+		w := r / 3
+		path.MoveTo(x-w, y-r)
+		path.LineTo(x+w, y-r)
+		path.LineTo(x+w, y-w)
+		path.LineTo(x+r, y-w)
+		path.LineTo(x+r, y+w)
+		path.LineTo(x+w, y+w)
+		path.LineTo(x+w, y+r)
+		path.LineTo(x-w, y+r)
+		path.LineTo(x-w, y+w)
+		path.LineTo(x-r, y+w)
+		path.LineTo(x-r, y-w)
+		path.LineTo(x-w, y-w)
+		path.Close()
+	case Hexagon:
+		polygon(6, 0)
+	}
+	return path
+}
+
+var (
+	whiteImage    = ebiten.NewImage(3, 3)
+	whiteSubImage = whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+)
+
+func init() {
+	whiteImage.Fill(color.White)
+}
+
+// DrawFilledShape fills the shape centered at (x, y) which fits in a circle of radius r.
+func DrawFilledShape(dst *ebiten.Image, s Shape, x, y, r float32, clr color.Color) {
+	vs, is := s.Path(x, y, r).AppendVerticesAndIndicesForFilling(nil, nil)
+	drawVertices(dst, vs, is, clr)
+}
+
+// StrokeShape strokes the outline of the shape centered at (x, y) which fits in a circle of radius r.
+func StrokeShape(dst *ebiten.Image, s Shape, x, y, r, strokeWidth float32, clr color.Color) {
+	vs, is := s.Path(x, y, r).AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+		Width:    strokeWidth,
+		LineJoin: vector.LineJoinMiter,
+	})
+	drawVertices(dst, vs, is, clr)
+}
+
+// drawVertices draws triangles in a solid color.
+func drawVertices(dst *ebiten.Image, vs []ebiten.Vertex, is []uint16, clr color.Color) {
+	r, g, b, a := clr.RGBA()
+	for i := range vs {
+		vs[i].SrcX = 1
+		vs[i].SrcY = 1
+		vs[i].ColorR = float32(r) / 0xffff
+		vs[i].ColorG = float32(g) / 0xffff
+		vs[i].ColorB = float32(b) / 0xffff
+		vs[i].ColorA = float32(a) / 0xffff
+	}
+	op := &ebiten.DrawTrianglesOptions{}
+	op.ColorScaleMode = ebiten.ColorScaleModePremultipliedAlpha
+	op.AntiAlias = true
+	dst.DrawTriangles(vs, is, whiteSubImage, op)
+}
@@ -0,0 +1,36 @@
+//go:build js
+
+package main
+
+import "syscall/js"
+
+// sharePuzzle puts the code of the puzzle in the URL of the page and copies the URL to the clipboard,
+// so that the page starts the puzzle when the URL is opened. It returns the channel receiving the message to be shown.
+func sharePuzzle(code string) <-chan string {
+	location := js.Global().Get("location")
+	url := location.Get("origin").String() + location.Get("pathname").String() + "?puzzle=" + code
+	js.Global().Get("history").Call("replaceState", nil, "", url)
+	clipboard := js.Global().Get("navigator").Get("clipboard")
+	if !clipboard.Truthy() {
+		return message("Link to the puzzle is in the address bar")
+	}
+	clipboard.Call("writeText", url)
+	return message("Copied the link to the puzzle")
+}
+
+// shareText copies the text to the clipboard, and returns the channel receiving the message to be shown.
+func shareText(text string) <-chan string {
+	clipboard := js.Global().Get("navigator").Get("clipboard")
+	if !clipboard.Truthy() {
+		return message("Unable to copy to the clipboard")
+	}
+	clipboard.Call("writeText", text)
+	return message("Copied to the clipboard")
+}
+
+// message returns a channel which has already received the message, as the clipboard of the browser does not block.
+func message(m string) <-chan string {
+	c := make(chan string, 1)
+	c <- m
+	return c
+}
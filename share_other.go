@@ -0,0 +1,31 @@
+//go:build !js
+
+package main
+
+import "log"
+
+// sharePuzzle copies the code of the puzzle, which is started by running the game with the -puzzle flag, in the background,
+// and returns the channel receiving the message to be shown with the code.
+func sharePuzzle(code string) <-chan string {
+	return copyInBackground(code, "Copied puzzle code "+code, "Puzzle code: "+code)
+}
+
+// shareText copies the text to the clipboard in the background, and returns the channel receiving the message to be shown.
+func shareText(text string) <-chan string {
+	return copyInBackground(text, "Copied to the clipboard", "Unable to copy to the clipboard")
+}
+
+// copyInBackground copies the text to the clipboard without blocking the game,
+// and returns the channel receiving the message copied, or failed if the clipboard is not available.
+func copyInBackground(text, copied, failed string) <-chan string {
+	c := make(chan string, 1)
+	go func() {
+		if err := copyToClipboard(text); err != nil {
+			log.Println(err)
+			c <- failed
+			return
+		}
+		c <- copied
+	}()
+	return c
+}
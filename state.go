@@ -11,6 +11,17 @@ type State interface {
 // StateMachine manages state transitions and delegates Update and Draw calls to the current state.
 type StateMachine struct {
 	Current State
+	width   int
+	height  int
+	scale   float64
+}
+
+// Switch makes the state current, notifying it of the screen size if known.
+func (s *StateMachine) Switch(next State) {
+	s.Current = next
+	if s.width > 0 && s.height > 0 {
+		s.Resize(s.width, s.height, s.scale)
+	}
 }
 
 // Update delegates the update call to the current state.
@@ -22,3 +33,16 @@ func (s *StateMachine) Update() error {
 func (s *StateMachine) Draw(screen *ebiten.Image) {
 	s.Current.Draw(screen)
 }
+
+// Resizer is an interface for game states which follow the size of the screen.
+type Resizer interface {
+	Resize(width, height int, scale float64)
+}
+
+// Resize notifies the current state of the new screen size if it is a Resizer.
+func (s *StateMachine) Resize(width, height int, scale float64) {
+	s.width, s.height, s.scale = width, height, scale
+	if r, ok := s.Current.(Resizer); ok {
+		r.Resize(width, height, scale)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ebitenui/ebitenui"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// StatsState shows the stats of the player, and returns to the game on Back.
+type StatsState struct {
+	UI      *ebitenui.UI
+	game    *GameState // game to return to
+	stats   *hyper.Stats
+	width   int
+	height  int
+	scale   float64
+	resized bool // whether the UI is to be rebuilt on the next update
+}
+
+// NewStatsState creates a StatsState of the stats, returning to the game which must have Machine.
+func NewStatsState(game *GameState, stats *hyper.Stats) (*StatsState, error) {
+	s := &StatsState{
+		game:   game,
+		stats:  stats,
+		width:  DEFAULT_SCREEN_WIDTH,
+		height: DEFAULT_SCREEN_HEIGHT,
+		scale:  1,
+	}
+	if err := s.applyLayout(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Resize schedules to rebuild the UI for the new screen size on the next update.
+func (s *StatsState) Resize(width, height int, scale float64) {
+	if s.width != width || s.height != height || s.scale != scale {
+		s.width, s.height, s.scale = width, height, scale
+		s.resized = true
+	}
+}
+
+// applyLayout rebuilds the UI to fit the screen.
+func (s *StatsState) applyLayout() error {
+	ui, err := s.createUI()
+	if err != nil {
+		return err
+	}
+	s.UI = ui
+	return nil
+}
+
+// Update handles the UI and returns to the game on Escape.
+func (s *StatsState) Update() error {
+	if s.resized {
+		s.resized = false
+		if err := s.applyLayout(); err != nil {
+			return err
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.back()
+		return nil
+	}
+	s.UI.Update()
+	return nil
+}
+
+// back returns to the game.
+func (s *StatsState) back() {
+	s.game.Machine.Switch(s.game)
+}
+
+// lines returns the text of the stats line by line.
+func (s *StatsState) lines() []string {
+	st := s.stats
+	solved := 0.0
+	if st.Played > 0 {
+		solved = float64(st.Solved) / float64(st.Played) * 100
+	}
+	lines := []string{
+		"Statistics",
+		"",
+		fmt.Sprintf("Played: %d  Solved: %d (%.0f%%)", st.Played, st.Solved, solved),
+		fmt.Sprintf("Steps: %.1f on average, %d at best", st.AverageSteps(), st.BestSteps),
+		fmt.Sprintf("Time: %s on average, %s at best", formatDuration(st.AverageTime()), formatDuration(st.BestTime)),
+		fmt.Sprintf("Streak: %d now, %d at best", st.Streak, st.BestStreak),
+		fmt.Sprintf("Undos: %d  Resets: %d", st.Undos, st.Resets),
+		"",
+	}
+	for _, c := range hyper.AllColors {
+		cs, ok := st.Colors[c]
+		if !ok {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s goal: %d of %d solved (%.0f%%)", c, cs.Solved, cs.Played, st.SuccessRate(c)*100))
+	}
+	return lines
+}
+
+// formatDuration returns the duration in minutes and seconds.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// Draw renders the stats and the button.
+func (s *StatsState) Draw(screen *ebiten.Image) {
+	t := s.game.theme()
+	screen.Fill(t.Background)
+	lineHeight := float32(24 * s.scale)
+	lines := s.lines()
+	y := float32(s.height)/2 - lineHeight*float32(len(lines)+2)/2
+	for _, line := range lines {
+		center := Position{float32(s.width) / 2, y}
+		s.game.renderer.DrawLetter(screen, line, center, float32(14*s.scale), t.Wall)
+		y += lineHeight
+	}
+	s.UI.Draw(screen)
+}
+
+// createUI creates and returns the UI container with the Back button at the bottom of the stats.
+func (s *StatsState) createUI() (*ebitenui.UI, error) {
+	root := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewAnchorLayout()),
+	)
+	clicked := func(args *widget.ButtonClickedEventArgs) {
+		s.game.emit(&GameEvent{Kind: ClickEvent})
+		s.back()
+	}
+	btn, err := createButton(s.game.ResourceLoader, &s.game.theme().Button, s.scale, "Back", clicked)
+	if err != nil {
+		return nil, err
+	}
+	btn.GetWidget().LayoutData = widget.AnchorLayoutData{
+		HorizontalPosition: widget.AnchorLayoutPositionCenter,
+		VerticalPosition:   widget.AnchorLayoutPositionEnd,
+		Padding:            widget.Insets{Bottom: int(32 * s.scale)},
+	}
+	root.AddChild(btn)
+
+	return &ebitenui.UI{
+		Container: root,
+	}, nil
+}
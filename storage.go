@@ -0,0 +1,12 @@
+package main
+
+// STORAGE_NAME is the name of the directory, or the prefix of keys, to store data of the game in.
+const STORAGE_NAME = "hyper-tux"
+
+// Storage keeps small named data across sessions.
+// Load returns an error wrapping fs.ErrNotExist if nothing is stored under the name.
+type Storage interface {
+	Load(name string) ([]byte, error)
+	Store(name string, data []byte) error
+	Remove(name string) error
+}
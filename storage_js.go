@@ -0,0 +1,50 @@
+//go:build js
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall/js"
+)
+
+// LocalStorage stores data in localStorage of the page, under keys prefixed by Prefix.
+type LocalStorage struct {
+	Prefix string
+}
+
+// NewStorage returns the storage in localStorage of the page.
+func NewStorage() (Storage, error) {
+	if !js.Global().Get("localStorage").Truthy() {
+		return nil, errors.New("localStorage is not available")
+	}
+	return &LocalStorage{Prefix: STORAGE_NAME + "/"}, nil
+}
+
+// Load reads the item of the name.
+func (s *LocalStorage) Load(name string) ([]byte, error) {
+	v := js.Global().Get("localStorage").Call("getItem", s.Prefix+name)
+	if v.IsNull() {
+		return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+	}
+	return []byte(v.String()), nil
+}
+
+// Store writes the data to the item of the name.
+func (s *LocalStorage) Store(name string, data []byte) (err error) {
+	// setItem throws if the storage is full
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unable to store %s: %v", name, r)
+		}
+	}()
+	js.Global().Get("localStorage").Call("setItem", s.Prefix+name, string(data))
+	return nil
+}
+
+// Remove deletes the item of the name.
+func (s *LocalStorage) Remove(name string) error {
+	js.Global().Get("localStorage").Call("removeItem", s.Prefix+name)
+	return nil
+}
@@ -0,0 +1,45 @@
+//go:build !js
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FileStorage stores data as files in the directory.
+type FileStorage struct {
+	Dir string
+}
+
+// NewStorage returns the storage in the user's configuration directory.
+func NewStorage() (Storage, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return &FileStorage{Dir: filepath.Join(dir, STORAGE_NAME)}, nil
+}
+
+// Load reads the file of the name.
+func (s *FileStorage) Load(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.Dir, name))
+}
+
+// Store writes the data to the file of the name, creating the directory if needed.
+// The data is written to a temporary file first so that the file is not broken if the game is killed while writing.
+func (s *FileStorage) Store(name string, data []byte) error {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(s.Dir, name)
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Remove deletes the file of the name.
+func (s *FileStorage) Remove(name string) error {
+	return os.Remove(filepath.Join(s.Dir, name))
+}
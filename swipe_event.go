@@ -9,6 +9,7 @@ import (
 )
 
 // SwipeEvent represents a swipe gesture with start and end points.
+// End of a recognised swipe is the neighbour of Start in the direction of the swipe.
 type SwipeEvent struct {
 	Start, End hyper.Point
 }
@@ -33,15 +34,23 @@ func (e *SwipeEvent) Direction() hyper.Direction {
 // SwipeEventHandler is an interface for handling swipe input events.
 type SwipeEventHandler interface {
 	HandlePressed() (start *Position)
+	HandleDragging() (current *Position)
 	HandleReleased() (end *Position)
 }
 
 // SwipeEventDispatcher manages and dispatches swipe events from multiple event handlers.
+// Swipes are recognised in screen space by Recognizer before being converted into cells.
 type SwipeEventDispatcher struct {
 	q              *list.List // of *SwipeEvent
 	EventHandlers  []SwipeEventHandler
+	Recognizer     SwipeRecognizer
+	Layout         *Layout                  // to convert positions on the screen into cells
+	Filter         func(e *SwipeEvent) bool // whether to queue the event, or nil to queue all events
 	currentHandler SwipeEventHandler
 	start          *Position
+	samples        []swipeSample // latest positions of the pointer
+	frame          int
+	left           bool // whether the pointer has left the start cell
 }
 
 // NewSwipeEventDispatcher creates a new SwipeEventDispatcher with the given event handlers.
@@ -49,14 +58,17 @@ func NewSwipeEventDispatcher(handlers ...SwipeEventHandler) *SwipeEventDispatche
 	return &SwipeEventDispatcher{
 		q:             list.New(),
 		EventHandlers: handlers,
+		Recognizer:    DefaultSwipeRecognizer,
 	}
 }
 
 // Update processes input events and generates SwipeEvents from handlers.
 func (d *SwipeEventDispatcher) Update() error {
+	d.frame++
 	if d.currentHandler == nil {
 		d.handlePressed()
 	} else {
+		d.handleDragging()
 		d.handleReleased()
 	}
 	return nil
@@ -67,28 +79,75 @@ func (d *SwipeEventDispatcher) handlePressed() {
 		d.start = handler.HandlePressed()
 		if d.start != nil {
 			d.currentHandler = handler
+			d.samples = d.samples[:0]
+			d.left = false
+			d.sample(*d.start)
 			break
 		}
 	}
 }
 
+func (d *SwipeEventDispatcher) handleDragging() {
+	pos := d.currentHandler.HandleDragging()
+	if pos == nil {
+		return
+	}
+	d.sample(*pos)
+	start := d.Layout.ToPoint(*d.start)
+	if !start.Equals(d.Layout.ToPoint(*pos)) {
+		d.left = true
+	}
+}
+
 func (d *SwipeEventDispatcher) handleReleased() {
 	pos := d.currentHandler.HandleReleased()
 	if pos == nil {
 		return
 	}
+	d.sample(*pos)
 
-	start := d.start.ToPoint(CELL_SIZE)
-	end := pos.ToPoint(CELL_SIZE)
+	start := d.Layout.ToPoint(*d.start)
+	dir, ok := d.recognizer().Recognize(*d.start, *pos, velocity(d.samples))
+	// dragging back to the start cell cancels the swipe
+	cancelled := d.left && start.Equals(d.Layout.ToPoint(*pos))
 
 	d.start = nil
 	d.currentHandler = nil
 
-	if start.Equals(end) {
+	if !ok || cancelled {
+		return
+	}
+
+	d.Push(&SwipeEvent{start, start.Add(dir.Vector())})
+}
+
+// Dragging returns the cell where the ongoing swipe started and the direction recognised so far.
+// It returns false if there is no ongoing swipe or the swipe would be ignored or cancelled on release.
+func (d *SwipeEventDispatcher) Dragging() (start hyper.Point, dir hyper.Direction, ok bool) {
+	if d.currentHandler == nil || d.start == nil || len(d.samples) < 1 {
 		return
 	}
+	current := d.samples[len(d.samples)-1].Position
+	start = d.Layout.ToPoint(*d.start)
+	if d.left && start.Equals(d.Layout.ToPoint(current)) {
+		return start, 0, false
+	}
+	dir, ok = d.recognizer().Recognize(*d.start, current, velocity(d.samples))
+	return
+}
 
-	d.q.PushBack(&SwipeEvent{start, end})
+// recognizer returns Recognizer with thresholds converted into device pixels.
+func (d *SwipeEventDispatcher) recognizer() *SwipeRecognizer {
+	r := d.Recognizer.Scaled(d.Layout.Scale)
+	return &r
+}
+
+// sample records the position of the pointer at the current frame.
+func (d *SwipeEventDispatcher) sample(pos Position) {
+	d.samples = append(d.samples, swipeSample{pos, d.frame})
+	if len(d.samples) > SWIPE_SAMPLES {
+		d.samples = d.samples[len(d.samples)-SWIPE_SAMPLES:]
+	}
 }
 
 // Len returns the number of pending swipe events in the queue.
@@ -96,8 +155,11 @@ func (d *SwipeEventDispatcher) Len() int {
 	return d.q.Len()
 }
 
-// Push adds a swipe event to the queue.
+// Push adds a swipe event to the queue unless Filter rejects it.
 func (d *SwipeEventDispatcher) Push(ev *SwipeEvent) {
+	if d.Filter != nil && !d.Filter(ev) {
+		return
+	}
 	d.q.PushBack(ev)
 }
 
@@ -127,6 +189,15 @@ func (h *MouseEventHandler) HandlePressed() *Position {
 	return &Position{X: float32(x), Y: float32(y)}
 }
 
+// HandleDragging returns the cursor position while the left mouse button is pressed, otherwise nil.
+func (h *MouseEventHandler) HandleDragging() *Position {
+	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		return nil
+	}
+	x, y := ebiten.CursorPosition()
+	return &Position{X: float32(x), Y: float32(y)}
+}
+
 // HandleReleased returns the cursor position if the left mouse button is just released, otherwise nil.
 func (h *MouseEventHandler) HandleReleased() *Position {
 	if !inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
@@ -136,29 +207,70 @@ func (h *MouseEventHandler) HandleReleased() *Position {
 	return &Position{X: float32(x), Y: float32(y)}
 }
 
+// TouchInput reads the state of touches, so that touches can be replayed in tests.
+type TouchInput struct {
+	AppendJustPressedTouchIDs  func([]ebiten.TouchID) []ebiten.TouchID
+	AppendJustReleasedTouchIDs func([]ebiten.TouchID) []ebiten.TouchID
+	TouchPressDuration         func(ebiten.TouchID) int
+	TouchPosition              func(ebiten.TouchID) (int, int)
+	// TouchPositionInPreviousTick returns the last position of a just released touch, whose TouchPosition is no longer available.
+	TouchPositionInPreviousTick func(ebiten.TouchID) (int, int)
+}
+
+// EbitenTouchInput is the TouchInput of the touches on the screen.
+var EbitenTouchInput = &TouchInput{
+	AppendJustPressedTouchIDs:   inpututil.AppendJustPressedTouchIDs,
+	AppendJustReleasedTouchIDs:  inpututil.AppendJustReleasedTouchIDs,
+	TouchPressDuration:          inpututil.TouchPressDuration,
+	TouchPosition:               ebiten.TouchPosition,
+	TouchPositionInPreviousTick: inpututil.TouchPositionInPreviousTick,
+}
+
 // TouchEventHandler handles touch input events.
 type TouchEventHandler struct {
-	id ebiten.TouchID
+	Input *TouchInput // or nil for EbitenTouchInput
+	id    ebiten.TouchID
+}
+
+// input returns Input, or EbitenTouchInput if it is not set.
+func (h *TouchEventHandler) input() *TouchInput {
+	if h.Input == nil {
+		return EbitenTouchInput
+	}
+	return h.Input
 }
 
 // HandlePressed returns the position of the first newly pressed touch, otherwise nil.
 func (h *TouchEventHandler) HandlePressed() *Position {
-	touchIDs := inpututil.AppendJustPressedTouchIDs([]ebiten.TouchID{})
+	in := h.input()
+	touchIDs := in.AppendJustPressedTouchIDs([]ebiten.TouchID{})
 	if len(touchIDs) < 1 {
 		return nil
 	}
 	// handle only first input
-	x, y := ebiten.TouchPosition(touchIDs[0])
+	x, y := in.TouchPosition(touchIDs[0])
 	h.id = touchIDs[0]
 	return &Position{X: float32(x), Y: float32(y)}
 }
 
-// HandleReleased returns the position of a touch that was being tracked if it just released, otherwise nil.
+// HandleDragging returns the position of the touch being tracked while it is pressed, otherwise nil.
+func (h *TouchEventHandler) HandleDragging() *Position {
+	in := h.input()
+	if in.TouchPressDuration(h.id) < 1 {
+		return nil
+	}
+	x, y := in.TouchPosition(h.id)
+	return &Position{X: float32(x), Y: float32(y)}
+}
+
+// HandleReleased returns the last position of a touch that was being tracked if it just released, otherwise nil.
 func (h *TouchEventHandler) HandleReleased() *Position {
-	touchIDs := inpututil.AppendJustReleasedTouchIDs([]ebiten.TouchID{})
+	in := h.input()
+	touchIDs := in.AppendJustReleasedTouchIDs([]ebiten.TouchID{})
 	for _, touchID := range touchIDs {
 		if touchID == h.id {
-			x, y := ebiten.TouchPosition(touchID)
+			// the position of a released touch is (0, 0)
+			x, y := in.TouchPositionInPreviousTick(touchID)
 			return &Position{X: float32(x), Y: float32(y)}
 		}
 	}
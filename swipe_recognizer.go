@@ -0,0 +1,87 @@
+package main
+
+import (
+	"math"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// SWIPE_SAMPLES is the number of latest positions used to measure the velocity of a swipe.
+const SWIPE_SAMPLES = 4
+
+// SwipeRecognizer recognises swipe gestures in screen space.
+// Distances and velocity are in logical pixels.
+type SwipeRecognizer struct {
+	MinDistance   float32 // px; shorter swipes are ignored unless they are flicks
+	DeadZone      float64 // degrees; swipes closer than this to a diagonal are ignored as ambiguous
+	FlickVelocity float32 // px/s; faster swipes are flicks
+	FlickDistance float32 // px; flicks shorter than this are ignored to tolerate jitters of clicks
+}
+
+// DefaultSwipeRecognizer is the SwipeRecognizer used by NewSwipeEventDispatcher.
+var DefaultSwipeRecognizer = SwipeRecognizer{
+	MinDistance:   12,
+	DeadZone:      10,
+	FlickVelocity: 600,
+	FlickDistance: 4,
+}
+
+// Scaled returns a copy of the recognizer whose distances and velocity are multiplied by scale.
+func (r *SwipeRecognizer) Scaled(scale float64) SwipeRecognizer {
+	f := float32(scale)
+	return SwipeRecognizer{
+		MinDistance:   r.MinDistance * f,
+		DeadZone:      r.DeadZone,
+		FlickVelocity: r.FlickVelocity * f,
+		FlickDistance: r.FlickDistance * f,
+	}
+}
+
+// Recognize returns the direction of the swipe from start to end, moving at the given velocity in px/s.
+// It returns false if the swipe is too short or too close to a diagonal.
+func (r *SwipeRecognizer) Recognize(start, end Position, velocity float32) (hyper.Direction, bool) {
+	diff := end.Sub(start)
+	distance := diff.Len()
+	isFlick := velocity >= r.FlickVelocity && distance >= r.FlickDistance
+	if distance < r.MinDistance && !isFlick {
+		return 0, false
+	}
+
+	// angle from the horizontal axis in degrees, between 0 and 90
+	angle := math.Atan2(math.Abs(float64(diff.Y)), math.Abs(float64(diff.X))) * 180 / math.Pi
+	if math.Abs(angle-45) < r.DeadZone {
+		return 0, false
+	}
+
+	if angle < 45 {
+		if diff.X < 0 {
+			return hyper.West, true
+		}
+		return hyper.East, true
+	}
+	if diff.Y < 0 {
+		return hyper.North, true
+	}
+	return hyper.South, true
+}
+
+// swipeSample is a position of the pointer at a frame.
+type swipeSample struct {
+	Position
+	frame int
+}
+
+// velocity returns the speed of the pointer in px/s over the given samples.
+func velocity(samples []swipeSample) float32 {
+	if len(samples) < 2 {
+		return 0
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	frames := last.frame - first.frame
+	if frames < 1 {
+		return 0
+	}
+	diff := last.Sub(first.Position)
+	return diff.Len() * float32(ebiten.TPS()) / float32(frames)
+}
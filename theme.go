@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// THEMES_DIR is the directory in the assets to load theme files from.
+const THEMES_DIR = "themes"
+
+// ThemeColor is a color which is written as "#rrggbb" or "#rrggbbaa" in theme files.
+type ThemeColor struct {
+	color.NRGBA
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c ThemeColor) MarshalText() ([]byte, error) {
+	if c.A == 255 {
+		return []byte(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)), nil
+	}
+	return []byte(fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *ThemeColor) UnmarshalText(text []byte) error {
+	s := string(text)
+	c.A = 255
+	var err error
+	switch len(s) {
+	case 7:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	case 9:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A)
+	default:
+		err = fmt.Errorf("invalid color: %s", s)
+	}
+	return err
+}
+
+// ButtonTheme defines the appearance of buttons.
+// Images are used instead of colors if they are specified.
+type ButtonTheme struct {
+	Idle, Hover, Pressed, Text ThemeColor
+	IdleImage                  string `json:",omitempty"`
+	HoverImage                 string `json:",omitempty"`
+	PressedImage               string `json:",omitempty"`
+	Border                     int    // width of the nine-slice border of images in px
+}
+
+// Theme defines everything visual of the game.
+type Theme struct {
+	Name       string
+	Background ThemeColor
+	Grid       ThemeColor
+	Wall       ThemeColor
+	Center     ThemeColor
+	Outline    ThemeColor // outlines of actors
+	Robots     map[hyper.Color]ThemeColor
+	Offsets    map[hyper.Color]float32 // offsets of history trails in logical pixels
+	Button     ButtonTheme
+	Font       string                 `json:",omitempty"` // path to the font file, or empty for Go Regular
+	Sprites    map[hyper.Color]string `json:",omitempty"` // paths to the images of actors
+	contrast   *Theme                 // copy with HighContrastPalette, created on demand
+}
+
+// DefaultTheme is used when no theme file is available.
+var DefaultTheme = &Theme{
+	Name:       "Classic",
+	Background: ThemeColor{color.NRGBA{255, 255, 255, 255}},
+	Grid:       ThemeColor{color.NRGBA{200, 200, 200, 255}},
+	Wall:       ThemeColor{color.NRGBA{0, 0, 0, 255}},
+	Center:     ThemeColor{color.NRGBA{200, 200, 200, 255}},
+	Outline:    ThemeColor{color.NRGBA{0, 0, 0, 255}},
+	Robots: map[hyper.Color]ThemeColor{
+		hyper.Black:  {color.NRGBA{0, 0, 0, 255}},
+		hyper.Red:    {color.NRGBA{255, 0, 0, 255}},
+		hyper.Green:  {color.NRGBA{0, 255, 0, 255}},
+		hyper.Blue:   {color.NRGBA{0, 0, 255, 255}},
+		hyper.Yellow: {color.NRGBA{255, 255, 0, 255}},
+	},
+	Offsets: map[hyper.Color]float32{
+		hyper.Red:    0,
+		hyper.Green:  1,
+		hyper.Blue:   -1,
+		hyper.Yellow: 2,
+		hyper.Black:  -2,
+	},
+	Button: ButtonTheme{
+		Idle:    ThemeColor{color.NRGBA{170, 170, 180, 255}},
+		Hover:   ThemeColor{color.NRGBA{130, 130, 150, 255}},
+		Pressed: ThemeColor{color.NRGBA{130, 130, 150, 255}},
+		Text:    ThemeColor{color.NRGBA{0, 0, 0, 255}},
+	},
+}
+
+// LoadTheme reads a theme from the JSON file at the given path.
+// Paths in the theme are relative to the directory of the file.
+func LoadTheme(r *ResourceLoader, filename string) (*Theme, error) {
+	file, err := r.File(filename)
+	if err != nil {
+		return nil, err
+	}
+	t := &Theme{}
+	if err := json.NewDecoder(file).Decode(t); err != nil {
+		return nil, fmt.Errorf("error in %s: %w", filename, err)
+	}
+
+	dir := path.Dir(filename)
+	resolve := func(p string) string {
+		if p == "" {
+			return p
+		}
+		return path.Join(dir, p)
+	}
+	t.Font = resolve(t.Font)
+	t.Button.IdleImage = resolve(t.Button.IdleImage)
+	t.Button.HoverImage = resolve(t.Button.HoverImage)
+	t.Button.PressedImage = resolve(t.Button.PressedImage)
+	for c, p := range t.Sprites {
+		t.Sprites[c] = resolve(p)
+	}
+	return t, nil
+}
+
+// LoadThemes reads all theme files in the directory of the assets.
+// DefaultTheme is returned if there is no valid theme file.
+func LoadThemes(r *ResourceLoader, dir string) []*Theme {
+	themes := []*Theme{}
+	entries, err := fs.ReadDir(r.FS(), dir)
+	if err != nil {
+		log.Println(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		t, err := LoadTheme(r, path.Join(dir, entry.Name()))
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		themes = append(themes, t)
+	}
+	if len(themes) == 0 {
+		themes = append(themes, DefaultTheme)
+	}
+	return themes
+}
+
+// FindTheme returns the theme of the given name, or the first theme if it is not found.
+func FindTheme(themes []*Theme, name string) *Theme {
+	for _, t := range themes {
+		if t.Name == name {
+			return t
+		}
+	}
+	return themes[0]
+}
+
+// SelectTheme returns the theme selected in the settings, with the high-contrast palette if it is turned on.
+func SelectTheme(themes []*Theme, s *Settings) *Theme {
+	t := FindTheme(themes, s.Theme)
+	if s.HighContrast {
+		return t.highContrast()
+	}
+	return t
+}
+
+// highContrast returns the copy of the theme whose actors and goals are colored with HighContrastPalette.
+func (t *Theme) highContrast() *Theme {
+	if t.contrast == nil {
+		c := *t
+		c.Robots = map[hyper.Color]ThemeColor{}
+		for k, v := range HighContrastPalette {
+			c.Robots[k] = ThemeColor{color.NRGBAModel.Convert(v).(color.NRGBA)}
+		}
+		t.contrast = &c
+	}
+	return t.contrast
+}
+
+// Palette returns the colors of actors and goals.
+func (t *Theme) Palette() Palette {
+	p := Palette{}
+	for c, v := range t.Robots {
+		p[c] = v
+	}
+	return p
+}
+
+// Offset returns a visual offset value in logical pixels for drawing history trails of different colored actors.
+func (t *Theme) Offset(c hyper.Color) float32 {
+	return t.Offsets[c]
+}
+
+// Sprite returns the image of the actor of the given color, if any.
+func (t *Theme) Sprite(r *ResourceLoader, c hyper.Color) (*ebiten.Image, bool) {
+	p, ok := t.Sprites[c]
+	if !ok {
+		return nil, false
+	}
+	img, err := r.Image(p)
+	if err != nil {
+		log.Println(err)
+		return nil, false
+	}
+	return img, img != nil
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/fj68/hyper-tux-go/hyper"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// TUTORIAL_FILE is the path to the script of the tutorial in the assets.
+const TUTORIAL_FILE = "tutorial.json"
+
+// LoadTutorial reads the script of the tutorial in the assets.
+func LoadTutorial(r *ResourceLoader) (*hyper.Tutorial, error) {
+	file, err := r.File(TUTORIAL_FILE)
+	if err != nil {
+		return nil, err
+	}
+	t, err := hyper.ReadTutorial(file)
+	if err != nil {
+		return nil, fmt.Errorf("error in %s: %w", TUTORIAL_FILE, err)
+	}
+	return t, nil
+}
+
+// TutorialState plays the script of a tutorial step by step on a locked game.
+// Each step explains a rule, highlights the actor to move and the cell where it stops,
+// and allows only the move of the step until the actor reaches the cell.
+type TutorialState struct {
+	game     *GameState // game to return to, which also creates the games of steps
+	inner    *GameState // game played in the current step
+	tutorial *hyper.Tutorial
+	current  int  // index of the current step
+	done     bool // whether all steps are finished and the closing text is shown
+	width    int
+	height   int
+	scale    float64
+}
+
+// NewTutorialState creates a TutorialState starting the first step of the tutorial, returning to the game which must have Machine.
+func NewTutorialState(game *GameState, t *hyper.Tutorial) (*TutorialState, error) {
+	s := &TutorialState{
+		game:     game,
+		tutorial: t,
+		width:    DEFAULT_SCREEN_WIDTH,
+		height:   DEFAULT_SCREEN_HEIGHT,
+		scale:    1,
+	}
+	if err := s.start(0); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// step returns the current step.
+func (s *TutorialState) step() *hyper.TutorialStep {
+	return s.tutorial.Steps[s.current]
+}
+
+// start starts the i-th step, creating a new game if the step has its own puzzle.
+func (s *TutorialState) start(i int) error {
+	s.current = i
+	puzzle := s.step().Puzzle
+	if puzzle == nil {
+		return nil
+	}
+	// steps are validated on the board placed as the puzzle says
+	b, err := puzzle.NewBoard(hyper.Placement{})
+	if err != nil {
+		return err
+	}
+	inner, err := s.game.derive(b)
+	if err != nil {
+		return err
+	}
+	inner.Locked = true
+	inner.Profile = nil
+	inner.SwipeEventDispatcher.Filter = s.allows
+	inner.Resize(s.width, s.height, s.scale)
+	s.inner = inner
+	return nil
+}
+
+// allows reports whether the swipe is the move of the current step, notifying a bump otherwise.
+func (s *TutorialState) allows(e *SwipeEvent) bool {
+	step := s.step()
+	if actor, ok := s.inner.Board.ActorAt(e.Start); ok && !step.Reached(s.inner.Board) && step.Allows(actor.Color, e.Direction()) {
+		return true
+	}
+	s.inner.emit(&GameEvent{Kind: BumpEvent})
+	return false
+}
+
+// Resize forwards the new screen size to the game of the current step.
+func (s *TutorialState) Resize(width, height int, scale float64) {
+	s.width, s.height, s.scale = width, height, scale
+	s.inner.Resize(width, height, scale)
+}
+
+// Update plays the current step, moving on to the next one when the actor stops at the expected cell.
+// Escape leaves the tutorial at any time, and any input leaves it after the closing text is shown.
+func (s *TutorialState) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.back()
+		return nil
+	}
+	if s.done {
+		if anyJustPressed() {
+			s.back()
+		}
+		return nil
+	}
+
+	// keep the actor of the step selected so that keyboard moves apply to it
+	s.inner.selected = s.step().Color
+	s.inner.selecting = true
+	if err := s.inner.Update(); err != nil {
+		return err
+	}
+
+	if !s.step().Reached(s.inner.Board) || s.inner.animator.Animating() {
+		return nil
+	}
+	if s.current+1 < len(s.tutorial.Steps) {
+		return s.start(s.current + 1)
+	}
+	s.done = true
+	s.inner.selecting = false
+	return nil
+}
+
+// anyJustPressed reports whether any key, mouse button or touch is just pressed.
+func anyJustPressed() bool {
+	return len(inpututil.AppendJustPressedKeys(nil)) > 0 ||
+		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
+		len(inpututil.AppendJustPressedTouchIDs(nil)) > 0
+}
+
+// back returns to the game.
+func (s *TutorialState) back() {
+	s.game.Machine.Switch(s.game)
+}
+
+// Draw renders the game of the current step with the expected cell highlighted, and the text of the step over the list of moves.
+func (s *TutorialState) Draw(screen *ebiten.Image) {
+	s.inner.Draw(screen)
+	t := s.inner.theme()
+	l := s.inner.layout
+	r := s.inner.renderer
+
+	if s.done {
+		vector.DrawFilledRect(screen, 0, 0, float32(l.Width), float32(l.Height), Translucent(t.Background, 224), false)
+		center := Position{float32(l.Width) / 2, float32(l.Height) / 2}
+		r.DrawLetter(screen, s.tutorial.Done, center, l.Px(16), t.Wall)
+		r.DrawLetter(screen, "Press any key to continue", center.Add(Position{0, l.Px(32)}), l.Px(12), t.Wall)
+		return
+	}
+
+	step := s.step()
+	cellSize := l.CellSize
+	p := NewPosition(step.Expected, cellSize)
+	p = p.Add(Position{float32(l.Stage.Min.X), float32(l.Stage.Min.Y)})
+	vector.StrokeRect(screen, p.X, p.Y, cellSize, cellSize, l.Px(3), t.Robots[step.Color], false)
+
+	s.drawText(screen, l.History, fmt.Sprintf("Tutorial %d/%d", s.current+1, len(s.tutorial.Steps)), step.Text)
+}
+
+// drawText renders the title and the text wrapped into lines over the area.
+func (s *TutorialState) drawText(screen *ebiten.Image, area image.Rectangle, title, text string) {
+	t := s.inner.theme()
+	l := s.inner.layout
+	r := s.inner.renderer
+	vector.DrawFilledRect(screen, float32(area.Min.X), float32(area.Min.Y), float32(area.Dx()), float32(area.Dy()), Translucent(t.Background, 240), false)
+
+	size := l.Px(12)
+	lineHeight := size * 3 / 2
+	center := float32(area.Min.X+area.Max.X) / 2
+	y := float32(area.Min.Y) + lineHeight
+	r.DrawLetter(screen, title, Position{center, y}, l.Px(14), t.Wall)
+	// glyphs of the font are about half as wide as they are high
+	for _, line := range wrapText(text, int(float32(area.Dx())/(size*0.55))) {
+		y += lineHeight
+		r.DrawLetter(screen, line, Position{center, y}, size, t.Wall)
+	}
+}
+
+// wrapText splits the text into lines of at most width characters at spaces, splitting words longer than that such as puzzle codes.
+func wrapText(text string, width int) []string {
+	width = max(1, width)
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for runes := []rune(word); len(runes) > width; runes = []rune(word) {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}